@@ -0,0 +1,199 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import "time"
+
+// Article is a Global Knowledge Graph (GKG) 2.1 record, describing a single
+// document processed by GDELT.
+//
+// Fields prefixed with "Enhanced" carry the character offset of each item
+// within the document, while their plain counterparts are the GKG 1.0
+// representations, kept for backward compatibility.
+type Article struct {
+	// ID is the unique identifier of the GKG record (GKGRECORDID).
+	ID string `json:"id"`
+	// Date is the 15-minute timestamp, in "YYYYMMDDHHMMSS" format, of the
+	// batch in which the document was processed.
+	Date uint64 `json:"date"`
+	// SourceCollectionIdentifier identifies the source collection the
+	// document came from. It uses the same codes as Mention.MentionType.
+	SourceCollectionIdentifier MentionType `json:"sourceCollectionIdentifier"`
+	// SourceCommonName is a human-friendly identifier of the source of the
+	// document, such as the top-level domain of a web page.
+	SourceCommonName string `json:"sourceCommonName"`
+	// DocumentIdentifier is the unique external identifier of the document.
+	// For web documents this is the full URL.
+	DocumentIdentifier string `json:"documentIdentifier"`
+
+	Counts         []Count `json:"counts"`
+	EnhancedCounts []Count `json:"enhancedCounts"`
+
+	Themes         []string      `json:"themes"`
+	EnhancedThemes []NamedOffset `json:"enhancedThemes"`
+
+	Locations         []Location `json:"locations"`
+	EnhancedLocations []Location `json:"enhancedLocations"`
+
+	Persons         []string      `json:"persons"`
+	EnhancedPersons []NamedOffset `json:"enhancedPersons"`
+
+	Organizations         []string      `json:"organizations"`
+	EnhancedOrganizations []NamedOffset `json:"enhancedOrganizations"`
+
+	Tone Tone `json:"tone"`
+	// Dates lists the date references found in the document.
+	Dates []DateMention `json:"dates"`
+	// GCAM holds the Global Content Analysis Measures of the document,
+	// keyed by dimension identifier. The "wc" key holds the word count.
+	GCAM map[string]float64 `json:"gcam"`
+
+	// SharingImage is the URL of the image the document suggests for social
+	// sharing, when available.
+	SharingImage      string   `json:"sharingImage"`
+	RelatedImages     []string `json:"relatedImages"`
+	SocialImageEmbeds []string `json:"socialImageEmbeds"`
+	SocialVideoEmbeds []string `json:"socialVideoEmbeds"`
+
+	Quotations []Quotation `json:"quotations"`
+	// AllNames lists all proper names found in the document, including those
+	// that are neither persons nor organizations.
+	AllNames []NamedOffset `json:"allNames"`
+	// Amounts lists all precise numeric amounts found in the document.
+	Amounts []Amount `json:"amounts"`
+
+	TranslationInfo TranslationInfo `json:"translationInfo"`
+	Extras          ArticleExtras   `json:"extras"`
+}
+
+type ArticleExtras struct {
+	PageTitle string `xml:"PAGE_TITLE" json:"pageTitle"`
+}
+
+// DateTime converts Date int value to time.Time.
+func (a *Article) DateTime() (time.Time, error) {
+	return parseTimeDate(a.Date)
+}
+
+// Count is a mention of a numeric count of a given object, such as the
+// number of people killed or arrested.
+type Count struct {
+	// CountType is the kind of count, such as "KILL" or "ARREST".
+	CountType string `json:"countType"`
+	// Number is the count value.
+	Number int64 `json:"number"`
+	// ObjectType is the object that was counted, such as "protesters".
+	ObjectType string `json:"objectType"`
+	// Location is the location the count refers to, if any. ADM2Code is
+	// never set.
+	Location GeoData `json:"location"`
+	// CharOffset is the location within the document where the count was
+	// found. It is -1 for GKG 1.0 counts.
+	CharOffset int `json:"charOffset"`
+}
+
+// NamedOffset is a name, theme or other label found at a given character
+// offset within a document.
+type NamedOffset struct {
+	Name       string `json:"name"`
+	CharOffset int    `json:"charOffset"`
+}
+
+// Location is a location mentioned in a document.
+type Location struct {
+	GeoData
+	// CharOffset is the location within the document where the location
+	// was found. It is -1 for GKG 1.0 locations.
+	CharOffset int `json:"charOffset"`
+}
+
+// Tone collects the emotional dimensions of a document.
+type Tone struct {
+	// Tone is the average tone of the document, from -100 (extremely
+	// negative) to +100 (extremely positive).
+	Tone float64 `json:"tone"`
+	// PositiveScore is the percentage of all words in the document that
+	// were found to have a positive emotional connotation.
+	PositiveScore float64 `json:"positiveScore"`
+	// NegativeScore is the percentage of all words in the document that
+	// were found to have a negative emotional connotation.
+	NegativeScore float64 `json:"negativeScore"`
+	// Polarity is the percentage of words that had matches in the tonal
+	// dictionary, indicating how emotionally polarized the text is.
+	Polarity float64 `json:"polarity"`
+	// ActivityReferenceDensity is the percentage of words that were active
+	// words offering a very basic proxy of the overall "activeness" of the
+	// text.
+	ActivityReferenceDensity float64 `json:"activityReferenceDensity"`
+	// SelfGroupReferenceDensity is the percentage of all words that are
+	// pronouns, capturing a combination of social-media-style language and
+	// self-referential discourse.
+	SelfGroupReferenceDensity float64 `json:"selfGroupReferenceDensity"`
+	// WordCount is the total number of words in the document.
+	WordCount int `json:"wordCount"`
+}
+
+// DateMention is a date reference found in a document.
+type DateMention struct {
+	// Resolution is 4 for dates with a month, day and year, 3 for dates
+	// with a month and day, 2 for dates with a month only, and 1 for dates
+	// with a year only.
+	Resolution int `json:"resolution"`
+	Month      int `json:"month"`
+	Day        int `json:"day"`
+	Year       int `json:"year"`
+	CharOffset int `json:"charOffset"`
+}
+
+// Quotation is a quoted statement found in a document.
+type Quotation struct {
+	CharOffset int `json:"charOffset"`
+	// Length is the length of the quotation in characters.
+	Length int `json:"length"`
+	// Verb is the verb used to introduce the quotation, such as "said".
+	Verb  string `json:"verb"`
+	Quote string `json:"quote"`
+}
+
+// Amount is a precise numeric amount found in a document.
+type Amount struct {
+	Amount     float64 `json:"amount"`
+	Object     string  `json:"object"`
+	CharOffset int     `json:"charOffset"`
+}
+
+// TranslationInfo records provenance information for documents that were
+// machine translated. It is empty for English documents.
+type TranslationInfo struct {
+	// SourceLanguage is the ISO 639-2 code of the original language.
+	SourceLanguage string `json:"sourceLanguage"`
+	// Engine identifies the translation engine and model used.
+	Engine string `json:"engine"`
+}
+
+// DuplicateArticlePolicy tells how to handle GKG articles sharing the same
+// DocumentIdentifier within a batch.
+type DuplicateArticlePolicy uint8
+
+const (
+	// KeepFirstArticle keeps the first of the duplicate articles.
+	KeepFirstArticle DuplicateArticlePolicy = iota
+	// KeepLastArticle keeps the last of the duplicate articles.
+	KeepLastArticle
+	// KeepAllArticles keeps every duplicate article in Event.GKGArticles.
+	KeepAllArticles
+	// FailOnDuplicateArticle fails the whole batch.
+	FailOnDuplicateArticle
+)
@@ -0,0 +1,197 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Batch holds the events of a single 15-minute GDELT batch.
+//
+// Raw batches, returned by FetchLatestRawBatches and FetchRawBatchesBetween,
+// also hold every GKG article and mention of the batch, along with indexes
+// to join them with the events.
+type Batch struct {
+	// Time is the 15-minute timestamp of the batch.
+	Time time.Time
+	// Feed is the stream the batch belongs to.
+	Feed Feed
+	// MD5Sum is the MD5 sum of the batch export file.
+	MD5Sum string
+	// Events are the events of the batch that passed the Opts filter. For
+	// raw batches, only Opts.Filter is applied.
+	Events []*Event
+	// Articles are all the GKG articles of the batch, in file order,
+	// including the ones not matching any event. Only set for raw batches.
+	Articles []*Article
+	// Mentions are all the mentions of the batch, in file order. Only set
+	// for raw batches.
+	Mentions []*Mention
+	// ArticlesByURL maps each DocumentIdentifier to its GKG articles,
+	// resolved according to Opts.DuplicateArticles. Only set for raw batches.
+	ArticlesByURL map[string][]*Article
+	// EventsByURL maps each SourceURL to its events. Only set for raw batches.
+	EventsByURL map[string][]*Event
+	// MentionsByEventID maps each GlobalEventID to its mentions. Only set
+	// for raw batches.
+	MentionsByEventID map[uint64][]*Mention
+	// Attempts is the number of download attempts made for the batch
+	// files, including retries.
+	Attempts int
+	// DuplicateArticles is the number of GKG articles of the batch with an
+	// already seen DocumentIdentifier.
+	DuplicateArticles int
+}
+
+// getBatch downloads a single batch. Unlike EventIterator, it does not skip
+// batches whose files cannot be downloaded.
+func (f *Fetcher) getBatch(ctx context.Context, fr *fileReferences, opts Opts) (*Batch, error) {
+	b, err := f.openBatch(ctx, fr, opts)
+	if err != nil {
+		return nil, err
+	}
+	return b.collect(fr, newEventFilter(opts).accept, false)
+}
+
+// FetchLatestRawBatches returns the latest raw batches. See
+// FetchRawBatchesBetween.
+func (f *Fetcher) FetchLatestRawBatches(ctx context.Context, opts Opts) ([]*Batch, error) {
+	batches, err := f.latestBatches(ctx, opts.Translingual)
+	if err != nil {
+		return nil, err
+	}
+	return f.getRawBatches(ctx, batches, opts)
+}
+
+// FetchRawBatchesBetween returns the raw batches published between start
+// (inclusive) and end (exclusive).
+//
+// Raw batches hold every event, GKG article and mention, without any of the
+// filtering of FetchEventsBetween: only the Translingual, ParseErrorHandler,
+// DuplicateArticles, Filter and Validation options are honoured, and
+// mentions are always downloaded. Batches whose files cannot be downloaded are skipped.
+func (f *Fetcher) FetchRawBatchesBetween(ctx context.Context, start, end time.Time, opts Opts) ([]*Batch, error) {
+	batches, err := f.listBatches(ctx, start, end, opts.Translingual)
+	if err != nil {
+		return nil, err
+	}
+	return f.getRawBatches(ctx, batches, opts)
+}
+
+func (f *Fetcher) getRawBatches(ctx context.Context, frs []*fileReferences, opts Opts) (_ []*Batch, err error) {
+	opts.IncludeMentions = true
+	accept := func(ev *Event) bool {
+		return opts.Filter == nil || opts.Filter(ev)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	p := f.prefetchBatches(ctx, frs, opts)
+	defer func() {
+		cancel()
+		p.drain()
+	}()
+
+	result := make([]*Batch, 0, len(frs))
+	for {
+		res, ok := p.next()
+		if !ok {
+			break
+		}
+		if IsBadStatusCodeError(res.err) {
+			// Avoid hard failures because of missing files.
+			f.logger().Warn("failed to get GDELT batch", "batch", res.fr.Time(), "error", res.err)
+			continue
+		}
+		var batch *Batch
+		if res.err == nil {
+			batch, err = res.batch.collect(res.fr, accept, true)
+		} else {
+			err = res.err
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get batch %s: %w", res.fr.Time().Format(dateAddedTimeLayout), err)
+		}
+		result = append(result, batch)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// collect reads the events of an opened batch accepted by accept, closing
+// it. Raw batches also hold all the GKG articles and mentions, and the
+// indexes joining them.
+func (b *batchEventReader) collect(fr *fileReferences, accept func(*Event) bool, raw bool) (_ *Batch, err error) {
+	defer func() {
+		if e := b.Close(); e != nil && err == nil {
+			err = e
+		}
+	}()
+
+	batch := &Batch{
+		Time:   fr.Time(),
+		Feed:   fr.Feed(),
+		MD5Sum: fr.Export.MD5Sum,
+		Events: make([]*Event, 0),
+	}
+	if raw {
+		batch.Articles = b.articleList
+		batch.Mentions = b.mentionList
+		batch.ArticlesByURL = b.articles
+		batch.EventsByURL = make(map[string][]*Event)
+		batch.MentionsByEventID = b.mentions
+		if batch.Articles == nil {
+			batch.Articles = make([]*Article, 0)
+			batch.ArticlesByURL = make(map[string][]*Article)
+		}
+		if batch.Mentions == nil {
+			batch.Mentions = make([]*Mention, 0)
+			batch.MentionsByEventID = make(map[uint64][]*Mention)
+		}
+	}
+
+	for {
+		ev, err := b.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !accept(ev) {
+			continue
+		}
+		batch.Events = append(batch.Events, ev)
+		if raw {
+			batch.EventsByURL[ev.SourceURL] = append(batch.EventsByURL[ev.SourceURL], ev)
+		}
+	}
+	batch.Attempts = b.attempts
+	batch.DuplicateArticles = b.duplicateArticles
+	return batch, nil
+}
+
+// checkpoint returns the BatchCheckpoint identifying the batch.
+func (b *Batch) checkpoint() BatchCheckpoint {
+	return BatchCheckpoint{Time: b.Time, MD5Sum: b.MD5Sum}
+}
+
+func (frs *fileReferences) checkpoint() BatchCheckpoint {
+	return BatchCheckpoint{Time: frs.Time(), MD5Sum: frs.Export.MD5Sum}
+}
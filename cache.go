@@ -0,0 +1,145 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache is a directory storing downloaded GDELT zip files, so that
+// reprocessing a batch does not download it again.
+//
+// Files are named after the SHA-256 hash of their URL, and are verified
+// against the size and MD5 sum announced by the GDELT file lists before
+// being used. When MaxSize is exceeded, the least recently used files are
+// evicted.
+//
+// A Cache is safe for concurrent use within a process. Multiple processes
+// may share the same directory, at the cost of less accurate eviction.
+type Cache struct {
+	// Dir is the cache directory.
+	Dir string
+	// MaxSize is the maximum total size of the cached files, in bytes.
+	// Zero means no limit.
+	MaxSize int64
+
+	mu sync.Mutex
+}
+
+// cacheFileExt is the extension of the cached files. Files being
+// downloaded have an additional ".tmp" extension.
+const cacheFileExt = ".zip"
+
+// NewCache returns a new Cache stored in dir, creating the directory if
+// needed.
+func NewCache(dir string, maxSize int64) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &Cache{Dir: dir, MaxSize: maxSize}, nil
+}
+
+func (c *Cache) path(url string) string {
+	return filepath.Join(c.Dir, fmt.Sprintf("%x%s", sha256.Sum256([]byte(url)), cacheFileExt))
+}
+
+// get opens the cached copy of the referenced file, if present and valid,
+// returning its size. Invalid copies are removed.
+func (c *Cache) get(fr fileReference) (*os.File, int64, bool) {
+	path := c.path(fr.URL)
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, false
+	}
+
+	h := md5.New()
+	size, err := io.Copy(h, file)
+	if err != nil || fr.verify(size, h.Sum(nil)) != nil {
+		_ = file.Close()
+		_ = os.Remove(path)
+		return nil, 0, false
+	}
+
+	// The modification time tracks the last use, for LRU eviction.
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return file, size, true
+}
+
+// put moves a downloaded file, located in the cache directory, in place.
+func (c *Cache) put(tmp *os.File, fr fileReference) error {
+	path := c.path(fr.URL)
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return nil
+}
+
+// evict removes the least recently used files until the total size of the
+// cache is within MaxSize.
+func (c *Cache) evict() error {
+	if c.MaxSize <= 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := os.ReadDir(c.Dir)
+	if err != nil {
+		return err
+	}
+
+	var files []os.FileInfo
+	var total int64
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), cacheFileExt) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			// The file may have been evicted concurrently.
+			continue
+		}
+		files = append(files, info)
+		total += info.Size()
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().Before(files[j].ModTime())
+	})
+
+	// The most recent file is always kept, even if it exceeds MaxSize
+	// alone, since it is about to be used.
+	for i := 0; total > c.MaxSize && i < len(files)-1; i++ {
+		err := os.Remove(filepath.Join(c.Dir, files[i].Name()))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		total -= files[i].Size()
+	}
+	return nil
+}
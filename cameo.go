@@ -0,0 +1,195 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// cameoEventCodesData is a tab-separated table of the CAMEO event codes
+// used by GDELT, with their Goldstein scores, labels and descriptions.
+//
+//go:embed cameo_eventcodes.txt
+var cameoEventCodesData string
+
+// CameoEventCode describes an entry of the CAMEO event taxonomy.
+//
+// Codes form a three-level hierarchy: two-digit root codes, three-digit
+// base codes, and four-digit codes refining some of the base codes.
+type CameoEventCode struct {
+	Code        string
+	Label       string
+	Description string
+	// Goldstein is the default Goldstein scale score of the code, from -10
+	// (most conflictual) to +10 (most cooperative).
+	Goldstein float64
+
+	parent   *CameoEventCode
+	children []*CameoEventCode
+}
+
+var cameoEventCodes struct {
+	once  sync.Once
+	codes map[string]*CameoEventCode
+	roots []*CameoEventCode
+}
+
+func loadCameoEventCodes() {
+	codes := make(map[string]*CameoEventCode)
+	var roots []*CameoEventCode
+	lines := strings.Split(strings.TrimSpace(cameoEventCodesData), "\n")
+	for i, line := range lines[1:] {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 4 {
+			panic(fmt.Sprintf("gdelt: malformed CAMEO event code at line %d", i+2))
+		}
+		goldstein, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			panic(fmt.Sprintf("gdelt: malformed CAMEO Goldstein score at line %d: %v", i+2, err))
+		}
+		c := &CameoEventCode{
+			Code:        fields[0],
+			Goldstein:   goldstein,
+			Label:       fields[2],
+			Description: fields[3],
+		}
+		codes[c.Code] = c
+		if len(c.Code) == 2 {
+			roots = append(roots, c)
+			continue
+		}
+		c.parent = codes[c.Code[:len(c.Code)-1]]
+		if c.parent == nil {
+			panic(fmt.Sprintf("gdelt: CAMEO event code %q has no parent", c.Code))
+		}
+		c.parent.children = append(c.parent.children, c)
+	}
+	cameoEventCodes.codes = codes
+	cameoEventCodes.roots = roots
+}
+
+// LookupCameoEventCode returns the CAMEO taxonomy entry of the given event
+// code, which can be a root, base or full code.
+func LookupCameoEventCode(code string) (*CameoEventCode, bool) {
+	cameoEventCodes.once.Do(loadCameoEventCodes)
+	c, ok := cameoEventCodes.codes[code]
+	return c, ok
+}
+
+// CameoRootEventCodes returns the 20 root codes of the CAMEO taxonomy,
+// from "01" to "20".
+func CameoRootEventCodes() []*CameoEventCode {
+	cameoEventCodes.once.Do(loadCameoEventCodes)
+	return append([]*CameoEventCode(nil), cameoEventCodes.roots...)
+}
+
+// Parent returns the parent code, or nil for root codes.
+func (c *CameoEventCode) Parent() *CameoEventCode {
+	return c.parent
+}
+
+// Children returns the codes refining this one.
+func (c *CameoEventCode) Children() []*CameoEventCode {
+	return append([]*CameoEventCode(nil), c.children...)
+}
+
+// Root returns the root code this code belongs to.
+func (c *CameoEventCode) Root() *CameoEventCode {
+	r := c
+	for r.parent != nil {
+		r = r.parent
+	}
+	return r
+}
+
+// IsRoot reports whether c is one of the 20 root codes.
+func (c *CameoEventCode) IsRoot() bool {
+	return c.parent == nil
+}
+
+// QuadClass returns the quad class of the code, as found in
+// Event.QuadClass: 1 (Verbal Cooperation) for root codes 01-05, 2 (Material
+// Cooperation) for 06-08, 3 (Verbal Conflict) for 09-13 and 4 (Material
+// Conflict) for 14-20.
+func (c *CameoEventCode) QuadClass() int {
+	root, _ := strconv.Atoi(c.Root().Code)
+	switch {
+	case root <= 5:
+		return 1
+	case root <= 8:
+		return 2
+	case root <= 13:
+		return 3
+	default:
+		return 4
+	}
+}
+
+func (c *CameoEventCode) String() string {
+	return c.Code + " " + c.Label
+}
+
+// EventDescription returns the CAMEO label of the EventCode, or an empty
+// string if the code is unknown.
+func (e *Event) EventDescription() string {
+	if c, ok := LookupCameoEventCode(e.EventCode); ok {
+		return c.Label
+	}
+	return ""
+}
+
+// CameoEventCode returns the CAMEO taxonomy entry of the EventCode.
+func (e *Event) CameoEventCode() (*CameoEventCode, bool) {
+	return LookupCameoEventCode(e.EventCode)
+}
+
+// ValidateCameoCodes checks that EventCode, EventBaseCode and EventRootCode
+// belong to the CAMEO taxonomy and are consistent with each other, and that
+// QuadClass matches the root code. All the problems found are reported.
+func (e *Event) ValidateCameoCodes() error {
+	var errs []error
+	check := func(field, code string) *CameoEventCode {
+		c, ok := LookupCameoEventCode(code)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s %q is not a CAMEO event code", field, code))
+		}
+		return c
+	}
+	code := check("EventCode", e.EventCode)
+	base := check("EventBaseCode", e.EventBaseCode)
+	root := check("EventRootCode", e.EventRootCode)
+
+	if base != nil && len(base.Code) != 3 {
+		errs = append(errs, fmt.Errorf("EventBaseCode %q is not a base code", base.Code))
+	}
+	if root != nil && !root.IsRoot() {
+		errs = append(errs, fmt.Errorf("EventRootCode %q is not a root code", root.Code))
+	}
+	if code != nil && base != nil && !strings.HasPrefix(code.Code, base.Code) {
+		errs = append(errs, fmt.Errorf("EventCode %q does not belong to EventBaseCode %q", code.Code, base.Code))
+	}
+	if base != nil && root != nil && !strings.HasPrefix(base.Code, root.Code) {
+		errs = append(errs, fmt.Errorf("EventBaseCode %q does not belong to EventRootCode %q", base.Code, root.Code))
+	}
+	if root != nil && root.IsRoot() && e.QuadClass != root.QuadClass() {
+		errs = append(errs, fmt.Errorf("QuadClass %d does not match EventRootCode %q (expected %d)", e.QuadClass, root.Code, root.QuadClass()))
+	}
+	return errors.Join(errs...)
+}
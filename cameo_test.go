@@ -0,0 +1,155 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLookupCameoEventCode(t *testing.T) {
+	tests := []struct {
+		code      string
+		label     string
+		goldstein float64
+		parent    string
+		root      string
+		quadClass int
+		children  int
+	}{
+		{"14", "Protest", -6.5, "", "14", 4, 6},
+		{"145", "Protest violently, riot", -7.5, "14", "14", 4, 4},
+		{"1451", "Engage in violent protest for leadership change", -7.5, "145", "14", 4, 0},
+		{"0211", "Appeal for economic cooperation", 3.4, "021", "02", 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			c, ok := LookupCameoEventCode(tt.code)
+			if !ok {
+				t.Fatalf("code %q not found", tt.code)
+			}
+			if c.Code != tt.code || c.Label != tt.label || c.Goldstein != tt.goldstein {
+				t.Errorf("unexpected entry %+v", c)
+			}
+			parent := ""
+			if p := c.Parent(); p != nil {
+				parent = p.Code
+			}
+			if parent != tt.parent || c.IsRoot() != (tt.parent == "") {
+				t.Errorf("expected parent %q, actual %q", tt.parent, parent)
+			}
+			if r := c.Root(); r.Code != tt.root {
+				t.Errorf("expected root %q, actual %q", tt.root, r.Code)
+			}
+			if q := c.QuadClass(); q != tt.quadClass {
+				t.Errorf("expected QuadClass %d, actual %d", tt.quadClass, q)
+			}
+			if n := len(c.Children()); n != tt.children {
+				t.Errorf("expected %d children, actual %d", tt.children, n)
+			}
+		})
+	}
+
+	for _, code := range []string{"", "00", "21", "999", "14x"} {
+		if _, ok := LookupCameoEventCode(code); ok {
+			t.Errorf("unexpected entry for code %q", code)
+		}
+	}
+}
+
+func TestCameoEventCodesConsistency(t *testing.T) {
+	roots := CameoRootEventCodes()
+	if len(roots) != 20 {
+		t.Fatalf("expected 20 root codes, actual %d", len(roots))
+	}
+
+	visited := 0
+	var walk func(c, root *CameoEventCode)
+	walk = func(c, root *CameoEventCode) {
+		visited++
+		if found, ok := LookupCameoEventCode(c.Code); !ok || found != c {
+			t.Errorf("%s: not returned by LookupCameoEventCode", c)
+		}
+		if c.Root() != root || c.QuadClass() != root.QuadClass() {
+			t.Errorf("%s: expected root %s", c, root)
+		}
+		if len(c.Label) == 0 || len(c.Description) == 0 || c.Goldstein < -10 || c.Goldstein > 10 {
+			t.Errorf("%s: invalid entry %+v", c, c)
+		}
+		for _, child := range c.Children() {
+			if child.Parent() != c || len(child.Code) != len(c.Code)+1 || !strings.HasPrefix(child.Code, c.Code) {
+				t.Errorf("%s: inconsistent child %s", c, child)
+			}
+			walk(child, root)
+		}
+	}
+	for i, root := range roots {
+		if want := fmt.Sprintf("%02d", i+1); root.Code != want || !root.IsRoot() {
+			t.Errorf("root %d: expected code %q, actual %s", i, want, root)
+		}
+		walk(root, root)
+	}
+
+	if visited != len(cameoEventCodes.codes) {
+		t.Errorf("visited %d codes from the roots, expected %d", visited, len(cameoEventCodes.codes))
+	}
+	if want := strings.Count(strings.TrimSpace(cameoEventCodesData), "\n"); visited != want {
+		t.Errorf("visited %d codes, expected the %d table rows", visited, want)
+	}
+
+	wantQuadClasses := []int{1, 1, 1, 1, 1, 2, 2, 2, 3, 3, 3, 3, 3, 4, 4, 4, 4, 4, 4, 4}
+	for i, root := range roots {
+		if q := root.QuadClass(); q != wantQuadClasses[i] {
+			t.Errorf("%s: expected QuadClass %d, actual %d", root, wantQuadClasses[i], q)
+		}
+	}
+}
+
+func TestValidateCameoCodes(t *testing.T) {
+	tests := []struct {
+		name  string
+		event Event
+		want  []string
+	}{
+		{"valid leaf", Event{EventCode: "1451", EventBaseCode: "145", EventRootCode: "14", QuadClass: 4}, nil},
+		{"valid base", Event{EventCode: "190", EventBaseCode: "190", EventRootCode: "19", QuadClass: 4}, nil},
+		{"unknown code", Event{EventCode: "1459", EventBaseCode: "145", EventRootCode: "14", QuadClass: 4}, []string{`EventCode "1459" is not a CAMEO event code`}},
+		{"base not a base code", Event{EventCode: "1451", EventBaseCode: "1451", EventRootCode: "14", QuadClass: 4}, []string{`EventBaseCode "1451" is not a base code`}},
+		{"root not a root code", Event{EventCode: "1451", EventBaseCode: "145", EventRootCode: "145", QuadClass: 4}, []string{`EventRootCode "145" is not a root code`}},
+		{"code outside base", Event{EventCode: "1451", EventBaseCode: "143", EventRootCode: "14", QuadClass: 4}, []string{`does not belong to EventBaseCode "143"`}},
+		{"base outside root", Event{EventCode: "190", EventBaseCode: "190", EventRootCode: "18", QuadClass: 4}, []string{`does not belong to EventRootCode "18"`}},
+		{"quad class", Event{EventCode: "190", EventBaseCode: "190", EventRootCode: "19", QuadClass: 1}, []string{"QuadClass 1 does not match"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.event.ValidateCameoCodes()
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected errors %q", tt.want)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(err.Error(), w) {
+					t.Errorf("expected %q in %q", w, err)
+				}
+			}
+		})
+	}
+}
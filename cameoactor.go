@@ -0,0 +1,253 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// cameoActorCodesData is a tab-separated table of CAMEO actor codes, with
+// their kind, label, and the term used to describe an actor.
+//
+//go:embed cameo_actorcodes.txt
+var cameoActorCodesData string
+
+// cameoActorCode is an entry of the CAMEO actor code tables.
+type cameoActorCode struct {
+	label string
+	// term is used when composing actor descriptions: a demonym for
+	// countries, an adjective for religions, a noun for types.
+	term string
+}
+
+var cameoActorCodes struct {
+	once        sync.Once
+	countries   map[string]cameoActorCode
+	knownGroups map[string]cameoActorCode
+	ethnic      map[string]cameoActorCode
+	religions   map[string]cameoActorCode
+	types       map[string]cameoActorCode
+}
+
+func loadCameoActorCodes() {
+	t := &cameoActorCodes
+	t.countries = make(map[string]cameoActorCode)
+	t.knownGroups = make(map[string]cameoActorCode)
+	t.ethnic = make(map[string]cameoActorCode)
+	t.religions = make(map[string]cameoActorCode)
+	t.types = make(map[string]cameoActorCode)
+	tables := map[string]map[string]cameoActorCode{
+		"country":    t.countries,
+		"knowngroup": t.knownGroups,
+		"ethnic":     t.ethnic,
+		"religion":   t.religions,
+		"type":       t.types,
+	}
+	lines := strings.Split(strings.TrimSpace(cameoActorCodesData), "\n")
+	for i, line := range lines[1:] {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 4 {
+			panic(fmt.Sprintf("gdelt: malformed CAMEO actor code at line %d", i+2))
+		}
+		table, ok := tables[fields[0]]
+		if !ok {
+			panic(fmt.Sprintf("gdelt: unknown CAMEO actor code kind %q at line %d", fields[0], i+2))
+		}
+		table[fields[1]] = cameoActorCode{label: fields[2], term: fields[3]}
+	}
+}
+
+func lookupCameoActorCode(table *map[string]cameoActorCode, code string) (cameoActorCode, bool) {
+	cameoActorCodes.once.Do(loadCameoActorCodes)
+	c, ok := (*table)[code]
+	return c, ok
+}
+
+// CameoCountryName returns the name of the country or region identified
+// by the given CAMEO country code, such as "RUS" or "MEA".
+func CameoCountryName(code string) (string, bool) {
+	c, ok := lookupCameoActorCode(&cameoActorCodes.countries, code)
+	return c.label, ok
+}
+
+// CameoKnownGroupName returns the name of the organization identified by
+// the given CAMEO known group code, such as "NAT" or "UNO".
+func CameoKnownGroupName(code string) (string, bool) {
+	c, ok := lookupCameoActorCode(&cameoActorCodes.knownGroups, code)
+	return c.label, ok
+}
+
+// CameoEthnicGroupName returns the name of the ethnic group identified by
+// the given CAMEO ethnic code, such as "kur". Only a selection of the most
+// common ethnic groups is known.
+func CameoEthnicGroupName(code string) (string, bool) {
+	c, ok := lookupCameoActorCode(&cameoActorCodes.ethnic, strings.ToLower(code))
+	return c.label, ok
+}
+
+// CameoReligionName returns the name of the religion identified by the
+// given CAMEO religion code, such as "SUN" or "CTH".
+func CameoReligionName(code string) (string, bool) {
+	c, ok := lookupCameoActorCode(&cameoActorCodes.religions, code)
+	return c.label, ok
+}
+
+// CameoActorTypeName returns the name of the actor type identified by the
+// given CAMEO type code, such as "GOV" or "MIL".
+func CameoActorTypeName(code string) (string, bool) {
+	c, ok := lookupCameoActorCode(&cameoActorCodes.types, code)
+	return c.label, ok
+}
+
+// ActorCode is the breakdown of a composite CAMEO actor code, such as
+// "RUSMIL" (Russian military) or "IGOUNOKID" (UNICEF), into its three-letter
+// segments.
+type ActorCode struct {
+	Country string
+	Ethnic  string
+	// KnownGroups are ordered from the most general to the most specific.
+	KnownGroups []string
+	Religions   []string
+	Types       []string
+	// Unknown lists the segments which could not be recognized.
+	Unknown []string
+}
+
+// ParseActorCode breaks down a composite CAMEO actor code. The first segment
+// is looked up as a country or region; the following ones, as actor types,
+// known groups, religions or ethnic groups, in this order.
+func ParseActorCode(code string) ActorCode {
+	cameoActorCodes.once.Do(loadCameoActorCodes)
+	t := &cameoActorCodes
+	var ac ActorCode
+	for i := 0; i < len(code); i += 3 {
+		seg := code[i:min(i+3, len(code))]
+		if _, ok := t.countries[seg]; ok && i == 0 {
+			ac.Country = seg
+			continue
+		}
+		if _, ok := t.types[seg]; ok {
+			ac.Types = append(ac.Types, seg)
+			continue
+		}
+		if _, ok := t.knownGroups[seg]; ok {
+			ac.KnownGroups = append(ac.KnownGroups, seg)
+			continue
+		}
+		if _, ok := t.religions[seg]; ok {
+			ac.Religions = append(ac.Religions, seg)
+			continue
+		}
+		if _, ok := t.ethnic[strings.ToLower(seg)]; ok && len(ac.Ethnic) == 0 {
+			ac.Ethnic = seg
+			continue
+		}
+		ac.Unknown = append(ac.Unknown, seg)
+	}
+	return ac
+}
+
+// Describe returns a human-readable description of the actor code, such as
+// "Russian military" for "RUSMIL". Unknown segments are left out.
+func (ac ActorCode) Describe() string {
+	cameoActorCodes.once.Do(loadCameoActorCodes)
+	t := &cameoActorCodes
+	var words []string
+	if c, ok := t.countries[ac.Country]; ok {
+		words = append(words, c.term)
+	}
+	if c, ok := t.ethnic[strings.ToLower(ac.Ethnic)]; ok {
+		words = append(words, c.term)
+	}
+	for _, r := range ac.Religions {
+		words = append(words, t.religions[r].term)
+	}
+	if n := len(ac.KnownGroups); n > 0 {
+		// The name of the most specific group is more telling than the
+		// types of the actor.
+		words = append(words, t.knownGroups[ac.KnownGroups[n-1]].term)
+		return strings.Join(words, " ")
+	}
+	for _, ty := range ac.Types {
+		words = append(words, t.types[ty].term)
+	}
+	return strings.Join(words, " ")
+}
+
+// ParsedCode returns the breakdown of the composite Code.
+func (a *ActorData) ParsedCode() ActorCode {
+	return ParseActorCode(a.Code)
+}
+
+// Describe returns a human-readable description of the actor, such as
+// "Russian military", derived from its Code. If the code cannot be
+// described, the actor Name is returned.
+func (a *ActorData) Describe() string {
+	if d := a.ParsedCode().Describe(); len(d) > 0 {
+		return d
+	}
+	return a.Name
+}
+
+// CountryLabel returns the name of the actor CountryCode.
+func (a *ActorData) CountryLabel() string {
+	s, _ := CameoCountryName(a.CountryCode)
+	return s
+}
+
+// KnownGroupLabel returns the name of the actor KnownGroupCode.
+func (a *ActorData) KnownGroupLabel() string {
+	s, _ := CameoKnownGroupName(a.KnownGroupCode)
+	return s
+}
+
+// EthnicLabel returns the name of the actor EthnicCode.
+func (a *ActorData) EthnicLabel() string {
+	s, _ := CameoEthnicGroupName(a.EthnicCode)
+	return s
+}
+
+// Religion1Label returns the name of the actor Religion1Code.
+func (a *ActorData) Religion1Label() string {
+	s, _ := CameoReligionName(a.Religion1Code)
+	return s
+}
+
+// Religion2Label returns the name of the actor Religion2Code.
+func (a *ActorData) Religion2Label() string {
+	s, _ := CameoReligionName(a.Religion2Code)
+	return s
+}
+
+// Type1Label returns the name of the actor Type1Code.
+func (a *ActorData) Type1Label() string {
+	s, _ := CameoActorTypeName(a.Type1Code)
+	return s
+}
+
+// Type2Label returns the name of the actor Type2Code.
+func (a *ActorData) Type2Label() string {
+	s, _ := CameoActorTypeName(a.Type2Code)
+	return s
+}
+
+// Type3Label returns the name of the actor Type3Code.
+func (a *ActorData) Type3Label() string {
+	s, _ := CameoActorTypeName(a.Type3Code)
+	return s
+}
@@ -0,0 +1,103 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseActorCode(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected ActorCode
+		describe string
+	}{
+		{"RUSMIL", ActorCode{Country: "RUS", Types: []string{"MIL"}}, "Russian military"},
+		{"USAGOV", ActorCode{Country: "USA", Types: []string{"GOV"}}, "American government"},
+		{"MIL", ActorCode{Types: []string{"MIL"}}, "military"},
+		{"NAT", ActorCode{KnownGroups: []string{"NAT"}}, "North Atlantic Treaty Organization"},
+		{
+			"IGOUNOKID",
+			ActorCode{Types: []string{"IGO"}, KnownGroups: []string{"UNO", "KID"}},
+			"United Nations Children's Fund",
+		},
+		{
+			"AFGTALINS",
+			ActorCode{Country: "AFG", KnownGroups: []string{"TAL"}, Types: []string{"INS"}},
+			"Afghan Taliban",
+		},
+		{"IRQKURREB", ActorCode{Country: "IRQ", Ethnic: "KUR", Types: []string{"REB"}}, "Iraqi Kurd rebels"},
+		{"IRQSHI", ActorCode{Country: "IRQ", Religions: []string{"SHI"}}, "Iraqi Shia"},
+		{"MOSSUNUAF", ActorCode{Religions: []string{"MOS", "SUN"}, Types: []string{"UAF"}}, "Muslim Sunni armed group"},
+		{"RUSXYZMIL", ActorCode{Country: "RUS", Types: []string{"MIL"}, Unknown: []string{"XYZ"}}, "Russian military"},
+		{"RUSMI", ActorCode{Country: "RUS", Unknown: []string{"MI"}}, "Russian"},
+		{"XYZ", ActorCode{Unknown: []string{"XYZ"}}, ""},
+		{"", ActorCode{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			ac := ParseActorCode(tt.code)
+			if !reflect.DeepEqual(ac, tt.expected) {
+				t.Errorf("expected %+v, actual %+v", tt.expected, ac)
+			}
+			if d := ac.Describe(); d != tt.describe {
+				t.Errorf("expected description %q, actual %q", tt.describe, d)
+			}
+		})
+	}
+}
+
+func TestActorDataDescribe(t *testing.T) {
+	a := ActorData{Code: "RUSMIL", Name: "RUSSIA", CountryCode: "RUS", Type1Code: "MIL"}
+	if d := a.Describe(); d != "Russian military" {
+		t.Errorf("expected %q, actual %q", "Russian military", d)
+	}
+	if s := a.CountryLabel(); s != "Russia" {
+		t.Errorf("expected country %q, actual %q", "Russia", s)
+	}
+	if s := a.Type1Label(); s != "Military" {
+		t.Errorf("expected type %q, actual %q", "Military", s)
+	}
+
+	a = ActorData{Code: "XYZ", Name: "SOMEONE"}
+	if d := a.Describe(); d != "SOMEONE" {
+		t.Errorf("expected the name as fallback, actual %q", d)
+	}
+}
+
+func TestCameoActorCodeNames(t *testing.T) {
+	tests := []struct {
+		lookup   func(string) (string, bool)
+		code     string
+		expected string
+	}{
+		{CameoCountryName, "RUS", "Russia"},
+		{CameoKnownGroupName, "UNO", "United Nations"},
+		{CameoEthnicGroupName, "kur", "Kurd"},
+		{CameoEthnicGroupName, "KUR", "Kurd"},
+		{CameoReligionName, "CTH", "Catholic"},
+		{CameoActorTypeName, "GOV", "Government"},
+	}
+	for _, tt := range tests {
+		s, ok := tt.lookup(tt.code)
+		if !ok || s != tt.expected {
+			t.Errorf("%s: expected %q, actual %q (%v)", tt.code, tt.expected, s, ok)
+		}
+	}
+	if _, ok := CameoCountryName("MIL"); ok {
+		t.Error("expected a type code not to be a country")
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Feed identifies one of the GDELT publishing streams.
+type Feed string
+
+const (
+	// EnglishFeed is the stream of English-language sources.
+	EnglishFeed Feed = "english"
+	// TranslingualFeed is the GDELT Translingual stream of machine
+	// translated sources.
+	TranslingualFeed Feed = "translingual"
+)
+
+func (fd Feed) lastUpdateFile() string {
+	if fd == TranslingualFeed {
+		return lastUpdateTranslationFile
+	}
+	return lastUpdateFile
+}
+
+func (fd Feed) masterFileListFile() string {
+	if fd == TranslingualFeed {
+		return masterFileListTranslationFile
+	}
+	return masterFileListFile
+}
+
+// feeds returns the feeds selected by the translingual option.
+func feeds(translingual bool) []Feed {
+	if translingual {
+		return []Feed{EnglishFeed, TranslingualFeed}
+	}
+	return []Feed{EnglishFeed}
+}
+
+// BatchCheckpoint identifies the last processed batch of a feed.
+type BatchCheckpoint struct {
+	// Time is the 15-minute timestamp of the batch.
+	Time time.Time `json:"time"`
+	// MD5Sum is the MD5 sum of the batch export file.
+	MD5Sum string `json:"md5"`
+}
+
+// Checkpoint persists the last processed batch of each feed, allowing the
+// processing to resume where it stopped.
+type Checkpoint interface {
+	// Load returns the last processed batch of the feed. The boolean result
+	// is false if no batch was recorded yet.
+	Load(feed Feed) (BatchCheckpoint, bool, error)
+	// Save records the last processed batch of the feed.
+	Save(feed Feed, bc BatchCheckpoint) error
+}
+
+// MemoryCheckpoint is a Checkpoint kept in memory. The zero value is ready
+// to use. It is safe for concurrent use.
+type MemoryCheckpoint struct {
+	mu      sync.Mutex
+	batches map[Feed]BatchCheckpoint
+}
+
+func (c *MemoryCheckpoint) Load(feed Feed) (BatchCheckpoint, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	bc, ok := c.batches[feed]
+	return bc, ok, nil
+}
+
+func (c *MemoryCheckpoint) Save(feed Feed, bc BatchCheckpoint) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.batches == nil {
+		c.batches = make(map[Feed]BatchCheckpoint)
+	}
+	c.batches[feed] = bc
+	return nil
+}
+
+// FileCheckpoint is a Checkpoint stored as a JSON file. The file is
+// created on the first Save and replaced atomically on each one. It is safe
+// for concurrent use within a process.
+type FileCheckpoint struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// NewFileCheckpoint returns a new FileCheckpoint stored at path.
+func NewFileCheckpoint(path string) *FileCheckpoint {
+	return &FileCheckpoint{Path: path}
+}
+
+func (c *FileCheckpoint) Load(feed Feed) (BatchCheckpoint, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	batches, err := c.read()
+	if err != nil {
+		return BatchCheckpoint{}, false, err
+	}
+	bc, ok := batches[feed]
+	return bc, ok, nil
+}
+
+func (c *FileCheckpoint) Save(feed Feed, bc BatchCheckpoint) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	batches, err := c.read()
+	if err != nil {
+		return err
+	}
+	batches[feed] = bc
+	return c.write(batches)
+}
+
+func (c *FileCheckpoint) read() (map[Feed]BatchCheckpoint, error) {
+	batches := make(map[Feed]BatchCheckpoint)
+	data, err := os.ReadFile(c.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return batches, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	if err := json.Unmarshal(data, &batches); err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint %q: %w", c.Path, err)
+	}
+	return batches, nil
+}
+
+func (c *FileCheckpoint) write(batches map[Feed]BatchCheckpoint) (err error) {
+	data, err := json.MarshalIndent(batches, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.Path), filepath.Base(c.Path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	if _, err = tmp.Write(data); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err = os.Rename(tmp.Name(), c.Path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFileCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "checkpoint.json")
+	c := NewFileCheckpoint(path)
+
+	if _, ok, err := c.Load(EnglishFeed); err != nil || ok {
+		t.Fatalf("expected no checkpoint and no error, actual %v, %v", ok, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected Load not to create the file, actual %v", err)
+	}
+
+	english := BatchCheckpoint{Time: time.Date(2023, 5, 1, 10, 15, 0, 0, time.UTC), MD5Sum: "a1"}
+	translingual := BatchCheckpoint{Time: time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC), MD5Sum: "b2"}
+	if err := c.Save(EnglishFeed, english); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Save(TranslingualFeed, translingual); err != nil {
+		t.Fatal(err)
+	}
+	english.Time = english.Time.Add(15 * time.Minute)
+	english.MD5Sum = "c3"
+	if err := c.Save(EnglishFeed, english); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new instance reads what the previous one saved.
+	c = NewFileCheckpoint(path)
+	for feed, expected := range map[Feed]BatchCheckpoint{EnglishFeed: english, TranslingualFeed: translingual} {
+		bc, ok, err := c.Load(feed)
+		if err != nil || !ok {
+			t.Fatalf("%s: expected a checkpoint, actual %v, %v", feed, ok, err)
+		}
+		if !bc.Time.Equal(expected.Time) || bc.MD5Sum != expected.MD5Sum {
+			t.Errorf("%s: expected %+v, actual %+v", feed, expected, bc)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "checkpoint.json" {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("expected only the checkpoint file, actual %v", names)
+	}
+}
+
+func TestFileCheckpointCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	if err := os.WriteFile(path, []byte(`{"english": {"time": `), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c := NewFileCheckpoint(path)
+
+	_, _, err := c.Load(EnglishFeed)
+	if err == nil || !strings.Contains(err.Error(), "failed to decode checkpoint") {
+		t.Fatalf("expected a decoding error, actual %v", err)
+	}
+	if err := c.Save(EnglishFeed, BatchCheckpoint{MD5Sum: "a1"}); err == nil {
+		t.Error("expected Save not to overwrite a corrupt checkpoint")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"english": {"time": ` {
+		t.Errorf("expected the corrupt file to be left untouched, actual %q", data)
+	}
+}
+
+func TestFileCheckpointWriteError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "checkpoint.json")
+	c := NewFileCheckpoint(path)
+	err := c.Save(EnglishFeed, BatchCheckpoint{MD5Sum: "a1"})
+	if err == nil || !strings.Contains(err.Error(), "failed to write checkpoint") {
+		t.Errorf("expected a write error, actual %v", err)
+	}
+}
+
+func TestFileCheckpointConcurrentSave(t *testing.T) {
+	dir := t.TempDir()
+	c := NewFileCheckpoint(filepath.Join(dir, "checkpoint.json"))
+	start := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			feed := EnglishFeed
+			if i%2 == 1 {
+				feed = TranslingualFeed
+			}
+			bc := BatchCheckpoint{Time: start.Add(time.Duration(i) * 15 * time.Minute)}
+			if err := c.Save(feed, bc); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, feed := range []Feed{EnglishFeed, TranslingualFeed} {
+		if _, ok, err := c.Load(feed); err != nil || !ok {
+			t.Errorf("%s: expected a checkpoint, actual %v, %v", feed, ok, err)
+		}
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the checkpoint file, actual %d files", len(entries))
+	}
+}
+
+func TestMemoryCheckpoint(t *testing.T) {
+	var c MemoryCheckpoint
+	if _, ok, err := c.Load(EnglishFeed); err != nil || ok {
+		t.Fatalf("expected no checkpoint and no error, actual %v, %v", ok, err)
+	}
+	bc := BatchCheckpoint{Time: time.Date(2023, 5, 1, 10, 15, 0, 0, time.UTC), MD5Sum: "a1"}
+	if err := c.Save(EnglishFeed, bc); err != nil {
+		t.Fatal(err)
+	}
+	actual, ok, err := c.Load(EnglishFeed)
+	if err != nil || !ok || actual != bc {
+		t.Errorf("expected %+v, actual %+v (%v, %v)", bc, actual, ok, err)
+	}
+	if _, ok, _ := c.Load(TranslingualFeed); ok {
+		t.Error("expected no checkpoint for the translingual feed")
+	}
+}
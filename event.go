@@ -23,19 +23,19 @@ import (
 type Event struct {
 	// GlobalEventID is the globally unique identifier assigned to each event
 	// record that uniquely identifies it in GDELT master dataset.
-	GlobalEventID uint64
-	Day           int
-	MonthYear     int
-	Year          int
-	FractionDate  float64
+	GlobalEventID uint64  `json:"globalEventId"`
+	Day           int     `json:"day"`
+	MonthYear     int     `json:"monthYear"`
+	Year          int     `json:"year"`
+	FractionDate  float64 `json:"fractionDate"`
 
-	Actor1 ActorData
-	Actor2 ActorData
+	Actor1 ActorData `json:"actor1"`
+	Actor2 ActorData `json:"actor2"`
 
-	IsRootEvent int
+	IsRootEvent int `json:"isRootEvent"`
 	// EventCode is the raw CAMEO action code describing the action that Actor1
 	// performed upon Actor2.
-	EventCode string
+	EventCode string `json:"eventCode"`
 	// EventBaseCode is the level two leaf root node category, when applicable.
 	// CAMEO event codes are defined in a three-level taxonomy. For events at
 	// level three in the taxonomy, this yields its level two leaf root node.
@@ -44,40 +44,47 @@ type Event struct {
 	// This makes it possible to aggregate events at various resolutions of
 	// specificity. For events at levels two or one, this field will be set
 	// to EventCode.
-	EventBaseCode string
+	EventBaseCode string `json:"eventBaseCode"`
 	// EventRootCode is similar to EventBaseCode and defines the root-level
 	// category the event code falls under. For example, code "0251" ("Appeal
 	// for easing of administrative sanctions") has a root code of "02"
 	// ("Appeal"). This makes it possible to aggregate events at various
 	// resolutions of specificity. For events at levels two or one, this field
 	// will be set to EventCode.
-	EventRootCode  string
-	QuadClass      int
-	GoldsteinScale NullableFloat64
-	NumMentions    int
-	NumSources     int
-	NumArticles    int
-	AvgTone        float64
-
-	Actor1Geo GeoData
-	Actor2Geo GeoData
+	EventRootCode  string          `json:"eventRootCode"`
+	QuadClass      int             `json:"quadClass"`
+	GoldsteinScale NullableFloat64 `json:"goldsteinScale"`
+	NumMentions    int             `json:"numMentions"`
+	NumSources     int             `json:"numSources"`
+	NumArticles    int             `json:"numArticles"`
+	AvgTone        float64         `json:"avgTone"`
+
+	Actor1Geo GeoData `json:"actor1Geo"`
+	Actor2Geo GeoData `json:"actor2Geo"`
 	// ActionGeo captures the location information closest to the point in the
 	// event description that contains the actual statement of action and is
 	// the best location to use for placing events on a map or in other spatial
 	// context.
-	ActionGeo GeoData
+	ActionGeo GeoData `json:"actionGeo"`
 
 	// DateAdded stores the date the event was added to the master database in
 	// "YYYYMMDDHHMMSS" format in the UTC timezone.
-	DateAdded uint64
+	DateAdded uint64 `json:"dateAdded"`
 	// SourceURL records the URL or citation of the first news report it found
 	// this event in. In most cases this is the first report it saw the article
 	// in, but due to the timing and flow of news reports through the processing
 	// pipeline, this may not always be the very first report, but is at least
 	// in the first few reports.
-	SourceURL string
-
-	GKGArticle *Article
+	SourceURL string `json:"sourceUrl"`
+
+	GKGArticle *Article `json:"gkgArticle,omitempty"`
+	// GKGArticles lists every GKG article sharing the event SourceURL, in
+	// file order. It is only populated when Opts.DuplicateArticles is
+	// KeepAllArticles; GKGArticle is then the first of them.
+	GKGArticles []*Article `json:"gkgArticles,omitempty"`
+	// Mentions lists every mention of this event found in the same 15-minute
+	// batch. It is only populated when Opts.IncludeMentions is set.
+	Mentions []*Mention `json:"mentions,omitempty"`
 }
 
 // NullableFloat64 represents a float64 value that may be null.
@@ -101,48 +108,37 @@ func ParseNullableFloat64(value string) (NullableFloat64, error) {
 	return NullableFloat64{Float64: f, Valid: true}, nil
 }
 
-type Article struct {
-	ID                 string
-	DocumentIdentifier string
-	SharingImage       string
-	Extras             ArticleExtras
-}
-
-type ArticleExtras struct {
-	PageTitle string `xml:"PAGE_TITLE"`
-}
-
 type ActorData struct {
-	Code           string
-	Name           string
-	CountryCode    string
-	KnownGroupCode string
-	EthnicCode     string
-	Religion1Code  string
-	Religion2Code  string
-	Type1Code      string
-	Type2Code      string
-	Type3Code      string
+	Code           string `json:"code"`
+	Name           string `json:"name"`
+	CountryCode    string `json:"countryCode"`
+	KnownGroupCode string `json:"knownGroupCode"`
+	EthnicCode     string `json:"ethnicCode"`
+	Religion1Code  string `json:"religion1Code"`
+	Religion2Code  string `json:"religion2Code"`
+	Type1Code      string `json:"type1Code"`
+	Type2Code      string `json:"type2Code"`
+	Type3Code      string `json:"type3Code"`
 }
 
 type GeoData struct {
 	// Type specifies the geographic resolution of the match type.
-	Type GeoType
+	Type GeoType `json:"type"`
 	// Fullname is the full human-readable name of the matched location. In
 	// the case of a country it is simply the country name. For US and World
 	// states it is in the format of "State, Country Name", while for all other
 	// matches it is in the format of "City/Landmark, State, Country".
 	// This can be used to label locations when placing events on a map.
-	Fullname string
+	Fullname string `json:"fullname"`
 	// CountryCode is the 2-character FIPS10-4 country code for the location.
-	CountryCode string
-	ADM1Code    string
-	ADM2Code    string
+	CountryCode string `json:"countryCode"`
+	ADM1Code    string `json:"adm1Code"`
+	ADM2Code    string `json:"adm2Code"`
 	// Lat is the centroid latitude of the landmark for mapping.
-	Lat NullableFloat64
+	Lat NullableFloat64 `json:"lat"`
 	// Long is the centroid longitude of the landmark for mapping.
-	Long      NullableFloat64
-	FeatureID string
+	Long      NullableFloat64 `json:"long"`
+	FeatureID string          `json:"featureId"`
 }
 
 func (g *GeoData) CountryCodeISO31661() (string, error) {
@@ -169,7 +165,7 @@ const (
 )
 
 func GeoTypeFromInt(value int) (GeoType, bool) {
-	if value < 0 && value > 5 {
+	if value < 0 || value > 5 {
 		return 0, false
 	}
 	return GeoType(value), true
@@ -196,10 +192,19 @@ var dateAddedTimeLayout = "20060102150405"
 
 // DateAddedTime converts DateAdded int value to time.Time.
 func (e *Event) DateAddedTime() (time.Time, error) {
-	s := fmt.Sprintf("%014d", e.DateAdded)
-	if len(s) != 14 {
+	t, err := parseTimeDate(e.DateAdded)
+	if err != nil {
 		return time.Time{}, fmt.Errorf("unexpected DateAdded value %d", e.DateAdded)
 	}
+	return t, nil
+}
+
+// parseTimeDate converts a "YYYYMMDDHHMMSS" integer value to time.Time.
+func parseTimeDate(v uint64) (time.Time, error) {
+	s := fmt.Sprintf("%014d", v)
+	if len(s) != 14 {
+		return time.Time{}, fmt.Errorf("unexpected time value %d", v)
+	}
 	return time.Parse(dateAddedTimeLayout, s)
 }
 
@@ -0,0 +1,211 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultV1BaseURL is the location of the GDELT 1.0 events files and of
+// their index.
+const DefaultV1BaseURL = "http://data.gdeltproject.org/events/"
+
+const v1IndexFile = "index.html"
+
+// V1File is a GDELT 1.0 events file, as listed by the GDELT 1.0 index.
+type V1File struct {
+	Name string
+	URL  string
+	// Start (inclusive) and End (exclusive) delimit the period covered by
+	// the file: a day for the daily files, published since April 2013, or a
+	// month or a year for the historical backfiles.
+	Start time.Time
+	End   time.Time
+	// MD5Sum is empty if not listed.
+	MD5Sum string
+}
+
+// Daily reports whether the file is a daily export file, as opposed to a
+// historical backfile.
+func (vf *V1File) Daily() bool {
+	return vf.End.Sub(vf.Start) <= 24*time.Hour
+}
+
+var (
+	v1IndexItemRe = regexp.MustCompile(`(?i)<li>`)
+	v1IndexHrefRe = regexp.MustCompile(`(?i)href="([^"]+)"`)
+	v1IndexMD5Re  = regexp.MustCompile(`(?i)md5:\s*([0-9a-f]{32})`)
+	v1DailyRe     = regexp.MustCompile(`^(\d{8})\.export\.CSV\.zip$`)
+	v1MonthlyRe   = regexp.MustCompile(`^(\d{6})\.zip$`)
+	v1YearlyRe    = regexp.MustCompile(`^(\d{4})\.zip$`)
+)
+
+func (f *Fetcher) v1URL(name string) string {
+	base := f.V1BaseURL
+	if len(base) == 0 {
+		base = DefaultV1BaseURL
+	}
+	return strings.TrimSuffix(base, "/") + "/" + name
+}
+
+// FetchV1Index returns the GDELT 1.0 events files, daily and historical,
+// sorted by time.
+func (f *Fetcher) FetchV1Index(ctx context.Context) (files []*V1File, err error) {
+	url := f.v1URL(v1IndexFile)
+	_, err = f.retry(ctx, url, func() (err error) {
+		var resp []byte
+		resp, err = f.httpGet(ctx, url)
+		if err != nil {
+			return err
+		}
+		files, err = f.parseV1Index(string(resp))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GDELT 1.0 index from %q: %w", url, err)
+	}
+	return files, nil
+}
+
+// parseV1Index extracts the events files from the HTML index. Other files,
+// and unexpected entries, are skipped.
+func (f *Fetcher) parseV1Index(index string) ([]*V1File, error) {
+	var files []*V1File
+	for _, item := range v1IndexItemRe.Split(index, -1) {
+		m := v1IndexHrefRe.FindStringSubmatch(item)
+		if m == nil {
+			continue
+		}
+		name := path.Base(m[1])
+		vf, ok := parseV1FileName(name)
+		if !ok {
+			continue
+		}
+		vf.URL = f.v1URL(name)
+		if m := v1IndexMD5Re.FindStringSubmatch(item); m != nil {
+			vf.MD5Sum = strings.ToLower(m[1])
+		}
+		files = append(files, vf)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no events files found in GDELT 1.0 index")
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].Start.Before(files[j].Start)
+	})
+	return files, nil
+}
+
+// parseV1FileName recognizes the name of a daily, monthly or yearly events
+// file.
+func parseV1FileName(name string) (*V1File, bool) {
+	for _, p := range []struct {
+		re     *regexp.Regexp
+		layout string
+		end    func(time.Time) time.Time
+	}{
+		{v1DailyRe, "20060102", func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }},
+		{v1MonthlyRe, "200601", func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }},
+		{v1YearlyRe, "2006", func(t time.Time) time.Time { return t.AddDate(1, 0, 0) }},
+	} {
+		m := p.re.FindStringSubmatch(name)
+		if m == nil {
+			continue
+		}
+		start, err := time.Parse(p.layout, m[1])
+		if err != nil {
+			return nil, false
+		}
+		return &V1File{Name: name, Start: start, End: p.end(start)}, true
+	}
+	return nil, false
+}
+
+// OpenV1File downloads a GDELT 1.0 events file and returns a reader over
+// its events. Since GDELT 1.0 has no GKG join, the events have no
+// GKGArticle; historical backfiles also lack the SourceURL.
+//
+// The returned EventReader must be closed when done.
+func (f *Fetcher) OpenV1File(ctx context.Context, vf *V1File) (*EventReader, error) {
+	ze, err := f.openZipFile(ctx, fileReference{URL: vf.URL, MD5Sum: vf.MD5Sum, Time: vf.Start})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GDELT 1.0 file %q: %w", vf.Name, err)
+	}
+	r := newEventsCsvReader(ze)
+	r.url = vf.URL
+	return &EventReader{r: r, closer: ze}, nil
+}
+
+// FetchV1EventsBetween returns the events of the GDELT 1.0 files covering
+// the period between start (inclusive) and end (exclusive). Events are
+// selected by file, so that files partially overlapping the period are read
+// in full.
+//
+// Only the Filter, Validation and ParseErrorHandler options are honoured:
+// the others rely on GKG data, which GDELT 1.0 lacks.
+func (f *Fetcher) FetchV1EventsBetween(ctx context.Context, start, end time.Time, opts Opts) ([]*Event, error) {
+	files, err := f.FetchV1Index(ctx)
+	if err != nil {
+		return nil, err
+	}
+	evs := make([]*Event, 0)
+	for _, vf := range files {
+		if !vf.End.After(start) || !vf.Start.Before(end) {
+			continue
+		}
+		evs, err = f.readV1File(ctx, vf, opts, evs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return evs, nil
+}
+
+func (f *Fetcher) readV1File(ctx context.Context, vf *V1File, opts Opts, evs []*Event) (_ []*Event, err error) {
+	r, err := f.OpenV1File(ctx, vf)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if e := r.Close(); e != nil && err == nil {
+			err = e
+		}
+	}()
+	r.r.validate = opts.Validation != SkipValidation
+	for {
+		ev, err := r.r.read()
+		if err == io.EOF {
+			return evs, nil
+		}
+		if err != nil {
+			if err := f.handleParseError(opts, err); err != nil {
+				return nil, err
+			}
+			if ev == nil || opts.Validation == RejectInvalidEvents {
+				continue
+			}
+		}
+		if opts.Filter == nil || opts.Filter(ev) {
+			evs = append(evs, ev)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"context"
+	"crypto/md5"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serveV1Files serves GDELT 1.0 events files below /events/, along with
+// their index.
+func (s *gdeltServer) serveV1Files(t *testing.T) {
+	daily := zipFile(t, "20140601.export.CSV", exportV1DailyRow+"\n")
+	yearly := zipFile(t, "1979.csv", exportV1HistoricalRow+"\n")
+	monthly := zipFile(t, "201303.csv", strings.Replace(exportV1HistoricalRow, "1\t19790101", "2\t20130301", 1)+"\n")
+	index := fmt.Sprintf(`<HTML><HEAD><TITLE>GDELT 1.0 Event Database</TITLE></HEAD><BODY>
+<UL>
+<LI><A HREF="md5sums">md5sums</A>
+<LI><A HREF="filesizes">filesizes</A>
+<LI><A HREF="GDELT.MASTERREDUCEDV2.1979-2013.zip">GDELT.MASTERREDUCEDV2.1979-2013.zip</A> 1.5GB
+<LI><A HREF="20140601.export.CSV.zip">20140601.export.CSV.zip</A> %d bytes (MD5: %X)
+<LI><A HREF="201303.zip">201303.zip</A> 70MB
+<LI><A HREF="1979.zip">1979.zip</A> %d bytes (MD5: %x)
+</UL></BODY></HTML>`, len(daily), md5.Sum(daily), len(yearly), md5.Sum(yearly))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files["/events/"+v1IndexFile] = []byte(index)
+	s.files["/events/20140601.export.CSV.zip"] = daily
+	s.files["/events/1979.zip"] = yearly
+	s.files["/events/201303.zip"] = monthly
+}
+
+func (s *gdeltServer) v1Fetcher() *Fetcher {
+	f := s.fetcher()
+	f.V1BaseURL = s.URL + "/events/"
+	return f
+}
+
+func TestFetchV1Index(t *testing.T) {
+	s := newGDELTServer(t)
+	s.serveV1Files(t)
+
+	files, err := s.v1Fetcher().FetchV1Index(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	want := []struct {
+		name       string
+		start, end time.Time
+		daily      bool
+		hasMD5     bool
+	}{
+		{"1979.zip", date(1979, 1, 1), date(1980, 1, 1), false, true},
+		{"201303.zip", date(2013, 3, 1), date(2013, 4, 1), false, false},
+		{"20140601.export.CSV.zip", date(2014, 6, 1), date(2014, 6, 2), true, true},
+	}
+	if len(files) != len(want) {
+		t.Fatalf("expected %d files, actual %d", len(want), len(files))
+	}
+	for i, w := range want {
+		vf := files[i]
+		if vf.Name != w.name || !vf.Start.Equal(w.start) || !vf.End.Equal(w.end) || vf.Daily() != w.daily {
+			t.Errorf("file %d: expected %+v, actual %+v", i, w, vf)
+		}
+		if vf.URL != s.URL+"/events/"+w.name {
+			t.Errorf("file %d: unexpected URL %q", i, vf.URL)
+		}
+		if (len(vf.MD5Sum) == 32) != w.hasMD5 || vf.MD5Sum != "" && vf.MD5Sum != fmt.Sprintf("%x", md5.Sum(s.files["/events/"+w.name])) {
+			t.Errorf("file %d: unexpected MD5 sum %q", i, vf.MD5Sum)
+		}
+	}
+}
+
+func TestFetchV1IndexEmpty(t *testing.T) {
+	s := newGDELTServer(t)
+	s.files["/events/"+v1IndexFile] = []byte(`<HTML><UL><LI><A HREF="md5sums">md5sums</A></UL></HTML>`)
+	if _, err := s.v1Fetcher().FetchV1Index(context.Background()); err == nil {
+		t.Error("expected an error for an index without events files")
+	}
+}
+
+func TestOpenV1FileCached(t *testing.T) {
+	s := newGDELTServer(t)
+	s.serveV1Files(t)
+	cache, err := NewCache(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := s.v1Fetcher()
+	f.Cache = cache
+
+	ctx := context.Background()
+	files, err := f.FetchV1Index(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	daily := files[2]
+	// The index lists no file sizes: the second open is a cache hit, which
+	// must use the actual size of the cached file.
+	for i := 0; i < 2; i++ {
+		r, err := f.OpenV1File(ctx, daily)
+		if err != nil {
+			t.Fatalf("open %d: %v", i+1, err)
+		}
+		e, err := r.Read()
+		if err != nil {
+			t.Fatalf("open %d: %v", i+1, err)
+		}
+		if e.GlobalEventID != 286268549 {
+			t.Errorf("open %d: unexpected event %d", i+1, e.GlobalEventID)
+		}
+		if _, err := r.Read(); err != io.EOF {
+			t.Errorf("open %d: expected io.EOF, actual %v", i+1, err)
+		}
+		if err := r.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := s.zipHits()["/events/"+daily.Name]; n != 1 {
+		t.Errorf("%s downloaded %d times, expected once", daily.Name, n)
+	}
+}
+
+func TestFetchV1EventsBetween(t *testing.T) {
+	s := newGDELTServer(t)
+	s.serveV1Files(t)
+
+	f := s.v1Fetcher()
+	ctx := context.Background()
+	start, end := time.Date(1979, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2014, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	// The files partially overlapping the period are read in full.
+	events, err := f.FetchV1EventsBetween(ctx, start, end, Opts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := eventIDs(events), []uint64{1, 2, 286268549}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected events %v, actual %v", want, got)
+	}
+
+	events, err = f.FetchV1EventsBetween(ctx, start, time.Date(2013, 3, 1, 0, 0, 0, 0, time.UTC), Opts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := eventIDs(events), []uint64{1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected events %v, actual %v", want, got)
+	}
+
+	s.mu.Lock()
+	delete(s.files, "/events/201303.zip")
+	s.mu.Unlock()
+	if _, err := f.FetchV1EventsBetween(ctx, start, end, Opts{}); !IsBadStatusCodeError(err) {
+		t.Errorf("expected a missing file to fail, actual %v", err)
+	}
+}
@@ -16,22 +16,24 @@ package main
 
 import (
 	"fmt"
+	"log/slog"
+	"os"
 	"time"
 
 	"github.com/nlpodyssey/gdelt"
-	"github.com/rs/zerolog/log"
 )
 
 func main() {
 
-	log.Info().Msg("getting latest events")
+	slog.Info("getting latest events")
 
 	events, err := gdelt.FetchLatestEvents(gdelt.DefaultOpts)
 	if err != nil {
-		log.Fatal().Err(err).Msg("error fetching latest events")
+		slog.Error("error fetching latest events", "error", err)
+		os.Exit(1)
 	}
 
-	log.Info().Msgf("processing %d events", len(events))
+	slog.Info("processing events", "count", len(events))
 
 	for _, event := range events {
 		doc := struct {
@@ -0,0 +1,152 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+)
+
+// EventReader reads events from a GDELT export file in tab-separated
+// format, producing the same values as the network fetch functions.
+// Both the GDELT 2.0 and 1.0 formats are supported, and detected by the
+// number of columns of each record.
+type EventReader struct {
+	// Strict enables the validation of the events: those failing
+	// Event.Validate are returned by Read along with a *ParseError wrapping
+	// the *ValidationError values, which can be recognized using errors.As.
+	Strict bool
+
+	r      *eventsCsvReader
+	closer io.Closer
+}
+
+// NewEventReader returns a new EventReader reading uncompressed
+// tab-separated export records from r. Use OpenExportFile to read zip or
+// gzip compressed files.
+func NewEventReader(r io.Reader) *EventReader {
+	return &EventReader{r: newEventsCsvReader(r)}
+}
+
+// Read reads the next event. It returns io.EOF at the end of the input.
+// Malformed records are reported as *ParseError: reading can continue with
+// the following ones. In Strict mode, invalid events are returned together
+// with the error.
+func (r *EventReader) Read() (*Event, error) {
+	r.r.validate = r.Strict
+	return r.r.read()
+}
+
+// Close closes the underlying file, if the reader was created with
+// OpenExportFile. Otherwise, it does nothing.
+func (r *EventReader) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	return r.closer.Close()
+}
+
+var (
+	zipMagic  = []byte("PK\x03\x04")
+	gzipMagic = []byte("\x1f\x8b")
+)
+
+// OpenExportFile opens a GDELT export file for reading. The file can be
+// plain tab-separated text (.CSV), a zip archive containing a single export
+// file (.CSV.zip), or gzip compressed (.CSV.gz). The format is detected from
+// the file content rather than its name.
+//
+// The returned EventReader must be closed when done.
+func OpenExportFile(path string) (_ *EventReader, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+		}
+	}()
+
+	r, err := openExportFile(f)
+	if err != nil {
+		return nil, err
+	}
+	r.r.url = path
+	return r, nil
+}
+
+func openExportFile(f *os.File) (*EventReader, error) {
+	br := bufio.NewReader(f)
+	magic, err := br.Peek(len(zipMagic))
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read %q: %w", f.Name(), err)
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, zipMagic):
+		return openZipExportFile(f)
+	case bytes.HasPrefix(magic, gzipMagic):
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("gzip reader error: %w", err)
+		}
+		r := NewEventReader(gr)
+		r.closer = multiCloser{gr, f}
+		return r, nil
+	default:
+		r := NewEventReader(br)
+		r.closer = f
+		return r, nil
+	}
+}
+
+func openZipExportFile(f *os.File) (*EventReader, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	zipReader, err := zip.NewReader(f, info.Size())
+	if err != nil {
+		return nil, fmt.Errorf("zip reader error: %w", err)
+	}
+	if len(zipReader.File) != 1 {
+		return nil, fmt.Errorf("want 1 file in zip, got %d", len(zipReader.File))
+	}
+	rc, err := zipReader.File[0].Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open zip file: %w", err)
+	}
+	r := NewEventReader(rc)
+	r.closer = multiCloser{rc, f}
+	return r, nil
+}
+
+// multiCloser closes all its elements in order, returning the first error.
+type multiCloser []io.Closer
+
+func (mc multiCloser) Close() (err error) {
+	for _, c := range mc {
+		if e := c.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
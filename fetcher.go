@@ -16,33 +16,41 @@ package gdelt
 
 import (
 	"archive/zip"
-	"bytes"
+	"context"
 	"crypto/md5"
-	"encoding/csv"
 	"errors"
 	"fmt"
-	"html"
 	"io"
+	"log/slog"
 	"net/http"
-	"regexp"
+	"os"
 	"strconv"
 	"strings"
 	"time"
-
-	"github.com/rs/zerolog/log"
 )
 
 const (
+	// DefaultBaseURL is the location of the GDELT 2.0 data file lists.
+	DefaultBaseURL = "http://data.gdeltproject.org/gdeltv2/"
+
 	// LastUpdateURL provides the last 15 Minutes CSV Data File List – English.
 	// (Updated every 15 minutes).
-	LastUpdateURL = "http://data.gdeltproject.org/gdeltv2/lastupdate.txt"
+	LastUpdateURL = DefaultBaseURL + lastUpdateFile
 
 	// LastUpdateTranslationURL provides the last 15 Minutes CSV Data File
 	// List – GDELT Translingual. (Updated every 15 minutes).
-	LastUpdateTranslationURL = "http://data.gdeltproject.org/gdeltv2/lastupdate-translation.txt"
+	LastUpdateTranslationURL = DefaultBaseURL + lastUpdateTranslationFile
 )
 
+const (
+	lastUpdateFile            = "lastupdate.txt"
+	lastUpdateTranslationFile = "lastupdate-translation.txt"
+)
+
+// DefaultOpts selects conflict events, which make the news headlines.
 var DefaultOpts = Opts{
+	// Threaten, Protest, Exhibit force posture, Coerce, Assault, Fight and
+	// Use unconventional mass violence. See LookupCameoEventCode.
 	AllowedCameoRootCodes: []string{"13", "14", "15", "17", "18", "19", "20"},
 	SkipDuplicates:        true,
 	SkipFutureEvents:      true,
@@ -55,6 +63,9 @@ var DefaultOpts = Opts{
 // using IsBadStatusCodeError.
 type BadStatusCodeError struct {
 	StatusCode int
+	// RetryAfter is the delay requested by the server through the
+	// Retry-After header, if any.
+	RetryAfter time.Duration
 }
 
 func (err BadStatusCodeError) Error() string {
@@ -76,101 +87,216 @@ type Opts struct {
 	MaxTitleLength        int
 	Translingual          bool
 	AllowedCameoRootCodes []string
+	// IncludeMentions enables downloading the Mentions table and attaching
+	// every mention to its Event.
+	IncludeMentions bool
+	// ParseErrorHandler is called for each malformed record. If nil,
+	// malformed records are logged and skipped.
+	ParseErrorHandler ParseErrorHandler
+	// DuplicateArticles selects how GKG articles sharing the same
+	// DocumentIdentifier are handled.
+	DuplicateArticles DuplicateArticlePolicy
+	// Filter, if not nil, is applied to the events passing all the other
+	// options.
+	Filter Filter
+	// Validation selects how the events failing Event.Validate are handled.
+	Validation Validation
+}
+
+// Fetcher downloads and parses GDELT data files.
+//
+// The zero value is ready to use: it performs requests with
+// http.DefaultClient against DefaultBaseURL.
+type Fetcher struct {
+	// Client is the HTTP client used for every request.
+	// If nil, http.DefaultClient is used.
+	Client *http.Client
+	// BaseURL is the location of the data file lists (lastupdate.txt and
+	// lastupdate-translation.txt). If empty, DefaultBaseURL is used.
+	BaseURL string
+	// Logger receives warnings about skipped data, and progress information
+	// at the debug level. If nil, slog.Default() is used.
+	Logger *slog.Logger
+	// Retry is the policy applied to every download. If nil, each download
+	// is attempted only once.
+	Retry *RetryPolicy
+	// Cache, if not nil, stores the downloaded data files, so that they are
+	// not downloaded again.
+	Cache *Cache
+	// Parallelism is the maximum number of data files downloaded at the
+	// same time. If zero, DefaultParallelism is used.
+	Parallelism int
+	// V1BaseURL is the location of the GDELT 1.0 events files. If empty,
+	// DefaultV1BaseURL is used.
+	V1BaseURL string
+
+	slots downloadSlots
+}
+
+// NewFetcher returns a new Fetcher using the given HTTP client and base URL.
+// A nil client or an empty base URL select the defaults.
+func NewFetcher(client *http.Client, baseURL string) *Fetcher {
+	return &Fetcher{Client: client, BaseURL: baseURL}
+}
+
+// DefaultFetcher is the Fetcher used by the package-level functions.
+var DefaultFetcher = &Fetcher{}
+
+// FetchLatestEvents returns the latest GDELT events using DefaultFetcher.
+func FetchLatestEvents(opts Opts) ([]*Event, error) {
+	return DefaultFetcher.FetchLatestEvents(context.Background(), opts)
+}
+
+// FetchLatestEventsContext is like FetchLatestEvents, but it uses the given
+// context for all HTTP requests.
+func FetchLatestEventsContext(ctx context.Context, opts Opts) ([]*Event, error) {
+	return DefaultFetcher.FetchLatestEvents(ctx, opts)
 }
 
 // FetchLatestEvents returns the latest GDELT events.
-func FetchLatestEvents(opts Opts) (_ []*Event, err error) {
-	a, err := getLatestEvents(LastUpdateURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get latest events from %q: %w", LastUpdateURL, err)
-	}
-	if !opts.Translingual {
-		return filterEvents(a, opts)
-	}
+// The context controls cancellation and deadlines of all HTTP requests.
+func (f *Fetcher) FetchLatestEvents(ctx context.Context, opts Opts) ([]*Event, error) {
+	return collectEvents(f.LatestEvents(ctx, opts))
+}
 
-	b, err := getLatestEvents(LastUpdateTranslationURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get latest events from %q: %w", LastUpdateTranslationURL, err)
+// url resolves the name of a data file list against the fetcher's BaseURL.
+func (f *Fetcher) url(name string) string {
+	base := f.BaseURL
+	if len(base) == 0 {
+		base = DefaultBaseURL
 	}
-	return filterEvents(append(a, b...), opts)
+	return strings.TrimSuffix(base, "/") + "/" + name
 }
 
-func filterEvents(evs []*Event, opts Opts) (_ []*Event, err error) {
-	result := make([]*Event, 0, len(evs))
-
-	visitedURLs := make(map[string]struct{}, len(evs))
+func (f *Fetcher) logger() *slog.Logger {
+	if f.Logger == nil {
+		return slog.Default()
+	}
+	return f.Logger
+}
 
-	for _, ev := range evs {
-		if len(ev.SourceURL) == 0 || ev.GKGArticle == nil || len(ev.GKGArticle.Extras.PageTitle) == 0 {
-			continue
-		}
-		publishedAt, err := ev.DateAddedTime()
-		if err != nil {
-			// Ignore the error, just discard the event.
-			continue
-		}
-		if opts.SkipFutureEvents && publishedAt.After(time.Now()) {
-			// Ignore future events.
-			continue
-		}
-		if len([]rune(ev.GKGArticle.Extras.PageTitle)) > opts.MaxTitleLength {
-			// Ignore events with long titles.
-			continue
-		}
-		if !isEventCodeAllowed(opts.AllowedCameoRootCodes, ev.EventRootCode) {
-			continue
-		}
-		if _, ok := visitedURLs[ev.SourceURL]; ok && opts.SkipDuplicates {
-			continue
-		}
-		result = append(result, ev)
-		visitedURLs[ev.SourceURL] = struct{}{}
+func (f *Fetcher) client() *http.Client {
+	if f.Client == nil {
+		return http.DefaultClient
 	}
+	return f.Client
+}
 
-	return result, nil
+// eventFilter applies Opts to a stream of events, one event at a time.
+type eventFilter struct {
+	opts        Opts
+	visitedURLs map[string]struct{}
 }
 
-func getLatestEvents(url string) (_ []*Event, err error) {
-	defer func() {
-		// Avoid hard failures because of bad server responses.
-		if IsBadStatusCodeError(err) {
-			log.Warn().Err(err).Str("URL", url).Msgf("failed to get latest GDELT events")
-			err = nil
-		}
-	}()
+func newEventFilter(opts Opts) *eventFilter {
+	return &eventFilter{
+		opts:        opts,
+		visitedURLs: make(map[string]struct{}),
+	}
+}
 
-	fr, err := getFileReferences(url)
+// accept reports whether the event passes the filter. Accepted events are
+// remembered, in order to skip duplicates.
+func (ef *eventFilter) accept(ev *Event) bool {
+	opts := ef.opts
+	if len(ev.SourceURL) == 0 || ev.GKGArticle == nil || len(ev.GKGArticle.Extras.PageTitle) == 0 {
+		return false
+	}
+	publishedAt, err := ev.DateAddedTime()
 	if err != nil {
-		return nil, err
+		// Ignore the error, just discard the event.
+		return false
+	}
+	if opts.SkipFutureEvents && publishedAt.After(time.Now()) {
+		// Ignore future events.
+		return false
 	}
+	if len([]rune(ev.GKGArticle.Extras.PageTitle)) > opts.MaxTitleLength {
+		// Ignore events with long titles.
+		return false
+	}
+	if !isEventCodeAllowed(opts.AllowedCameoRootCodes, ev.EventRootCode) {
+		return false
+	}
+	if opts.Filter != nil && !opts.Filter(ev) {
+		return false
+	}
+	if _, ok := ef.visitedURLs[ev.SourceURL]; ok && opts.SkipDuplicates {
+		return false
+	}
+	ef.visitedURLs[ev.SourceURL] = struct{}{}
+	return true
+}
 
-	evs, err := getEventsFromURL(fr.Export.URL, fr.Export.MD5Sum, fr.Export.Size)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get export data: %w", err)
+// latestBatches returns the file references of the latest English batch
+// and, if translingual is true, of the latest translation batch.
+func (f *Fetcher) latestBatches(ctx context.Context, translingual bool) ([]*fileReferences, error) {
+	urls := []string{f.url(lastUpdateFile)}
+	if translingual {
+		urls = append(urls, f.url(lastUpdateTranslationFile))
 	}
 
-	articles, err := getArticleFromURL(fr.GKG.URL, fr.GKG.MD5Sum, fr.GKG.Size)
+	refs := make([]*fileReferences, len(urls))
+	err := forEach(len(urls), func(i int) (err error) {
+		refs[i], err = f.getFileReferences(ctx, urls[i])
+		if IsBadStatusCodeError(err) {
+			// Avoid hard failures because of bad server responses.
+			f.logger().Warn("failed to get latest GDELT events", "URL", urls[i], "error", err)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get latest events from %q: %w", urls[i], err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get GKG data: %w", err)
+		return nil, err
 	}
 
-	am := make(map[string]*Article, len(articles))
-	for _, a := range articles {
-		if _, ok := am[a.DocumentIdentifier]; ok {
-			return nil, fmt.Errorf("duplicate document identifier in articles: %q", a.DocumentIdentifier)
+	result := make([]*fileReferences, 0, len(refs))
+	for _, fr := range refs {
+		if fr != nil {
+			result = append(result, fr)
 		}
-		am[a.DocumentIdentifier] = a
 	}
+	return result, nil
+}
 
-	for _, e := range evs {
-		a, ok := am[e.SourceURL]
+// indexArticles maps each article by its DocumentIdentifier, resolving
+// duplicates according to the policy. It also returns the number of
+// duplicates found.
+func indexArticles(articles []*Article, policy DuplicateArticlePolicy) (map[string][]*Article, int, error) {
+	am := make(map[string][]*Article, len(articles))
+	duplicates := 0
+	for _, a := range articles {
+		prev, ok := am[a.DocumentIdentifier]
 		if !ok {
+			am[a.DocumentIdentifier] = []*Article{a}
 			continue
 		}
-		e.GKGArticle = a
+		duplicates++
+		switch policy {
+		case KeepFirstArticle:
+		case KeepLastArticle:
+			prev[0] = a
+		case KeepAllArticles:
+			am[a.DocumentIdentifier] = append(prev, a)
+		case FailOnDuplicateArticle:
+			return nil, duplicates, fmt.Errorf("duplicate document identifier in articles: %q", a.DocumentIdentifier)
+		default:
+			return nil, duplicates, fmt.Errorf("invalid duplicate article policy: %d", policy)
+		}
 	}
+	return am, duplicates, nil
+}
 
-	return evs, nil
+// indexMentions groups the mentions by the GlobalEventID they refer to.
+func indexMentions(mentions []*Mention) map[uint64][]*Mention {
+	mm := make(map[uint64][]*Mention)
+	for _, m := range mentions {
+		mm[m.GlobalEventID] = append(mm[m.GlobalEventID], m)
+	}
+	return mm
 }
 
 func isEventCodeAllowed(allowedEventRootCodes []string, currentEventCode string) bool {
@@ -186,9 +312,23 @@ func isEventCodeAllowed(allowedEventRootCodes []string, currentEventCode string)
 }
 
 type fileReference struct {
+	// Size and MD5Sum are zero when unknown, as for the GDELT 1.0 files.
 	Size   int
 	MD5Sum string
 	URL    string
+	// Time is the 15-minute timestamp of the batch the file belongs to.
+	Time time.Time
+}
+
+// verify checks the size and MD5 sum of the downloaded file, when known.
+func (fr fileReference) verify(size int64, sum []byte) error {
+	if fr.Size > 0 && size != int64(fr.Size) {
+		return fmt.Errorf("expected content size %d, actual %d", fr.Size, size)
+	}
+	if len(fr.MD5Sum) > 0 {
+		return checkMD5Sum(sum, fr.MD5Sum)
+	}
+	return nil
 }
 
 type fileReferences struct {
@@ -197,37 +337,48 @@ type fileReferences struct {
 	GKG      fileReference
 }
 
-func getFileReferences(url string) (_ *fileReferences, err error) {
-	resp, err := httpGetFileReferences(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to HTTP get %q: %w", url, err)
+// Time returns the 15-minute timestamp of the batch.
+func (frs *fileReferences) Time() time.Time {
+	return frs.Export.Time
+}
+
+// Feed returns the stream the batch belongs to. GDELT Translingual files
+// are named "*.translation.*".
+func (frs *fileReferences) Feed() Feed {
+	if strings.Contains(frs.Export.URL, ".translation.") {
+		return TranslingualFeed
 	}
-	frs, err := parseFileReferencesResponse(resp)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse response from %q: %w", url, err)
+	return EnglishFeed
+}
+
+func (frs *fileReferences) set(fr fileReference) error {
+	switch {
+	case strings.HasSuffix(fr.URL, ".export.CSV.zip"):
+		frs.Export = fr
+	case strings.HasSuffix(fr.URL, ".mentions.CSV.zip"):
+		frs.Mentions = fr
+	case strings.HasSuffix(fr.URL, ".gkg.csv.zip"):
+		frs.GKG = fr
+	default:
+		return fmt.Errorf("unexpected suffix for URL: %q", fr.URL)
 	}
-	return frs, nil
+	return nil
 }
 
-func httpGetFileReferences(url string) (_ string, err error) {
-	resp, err := http.Get(url)
+func (f *Fetcher) getFileReferences(ctx context.Context, url string) (_ *fileReferences, err error) {
+	var resp []byte
+	_, err = f.retry(ctx, url, func() (err error) {
+		resp, err = f.httpGet(ctx, url)
+		return err
+	})
 	if err != nil {
-		return "", fmt.Errorf("HTTP getFileReferences error: %w", err)
-	}
-	defer func() {
-		if e := resp.Body.Close(); e != nil && err == nil {
-			err = e
-		}
-	}()
-	if resp.StatusCode != http.StatusOK {
-		return "", NewBadStatusCodeError(resp.StatusCode)
+		return nil, fmt.Errorf("failed to HTTP get %q: %w", url, err)
 	}
-
-	bs, err := io.ReadAll(resp.Body)
+	frs, err := parseFileReferencesResponse(string(resp))
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("failed to parse response from %q: %w", url, err)
 	}
-	return string(bs), err
+	return frs, nil
 }
 
 func parseFileReferencesResponse(resp string) (*fileReferences, error) {
@@ -248,207 +399,306 @@ func parseFileReferencesResponse(resp string) (*fileReferences, error) {
 }
 
 func parseFileReferencesRow(row string, frs *fileReferences) error {
+	fr, err := parseFileReference(row)
+	if err != nil {
+		return err
+	}
+	return frs.set(fr)
+}
+
+func parseFileReference(row string) (fileReference, error) {
 	fields := strings.Split(row, " ")
 	if len(fields) != 3 {
-		return fmt.Errorf("want 3 fields, got %d", len(fields))
+		return fileReference{}, fmt.Errorf("want 3 fields, got %d", len(fields))
 	}
 	size, err := strconv.Atoi(fields[0])
 	if err != nil {
-		return fmt.Errorf("failed to parse Size field as int: %q", fields[0])
+		return fileReference{}, fmt.Errorf("failed to parse Size field as int: %q", fields[0])
 	}
-
-	fr := fileReference{
+	t, err := parseBatchTime(fields[2])
+	if err != nil {
+		return fileReference{}, err
+	}
+	return fileReference{
 		Size:   size,
 		MD5Sum: fields[1],
 		URL:    fields[2],
-	}
-	switch {
-	case strings.HasSuffix(fr.URL, ".export.CSV.zip"):
-		frs.Export = fr
-	case strings.HasSuffix(fr.URL, ".mentions.CSV.zip"):
-		frs.Mentions = fr
-	case strings.HasSuffix(fr.URL, ".gkg.csv.zip"):
-		frs.GKG = fr
-	default:
-		return fmt.Errorf("unexpected suffix for URL: %q", fr.URL)
-	}
-	return nil
+		Time:   t,
+	}, nil
 }
 
-func getArticleFromURL(url, md5sum string, size int) ([]*Article, error) {
-	content, err := httpGet(url)
+// parseBatchTime extracts the batch timestamp from the name of a data file,
+// such as ".../20231010120000.export.CSV.zip".
+func parseBatchTime(url string) (time.Time, error) {
+	name := url[strings.LastIndexByte(url, '/')+1:]
+	ts, _, _ := strings.Cut(name, ".")
+	t, err := time.Parse(dateAddedTimeLayout, ts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to HTTP get %q: %w", url, err)
-	}
-
-	if len(content) != size {
-		return nil, fmt.Errorf("expected content size %d, actual %d", size, len(content))
+		return time.Time{}, fmt.Errorf("failed to parse batch timestamp of URL %q", url)
 	}
+	return t, nil
+}
 
-	err = checkMD5Sum(content, md5sum)
-	if err != nil {
-		return nil, fmt.Errorf("failed to validate %q: %w", url, err)
-	}
+// readArticles reads all the GKG articles of the zip entry, closing it.
+func (f *Fetcher) readArticles(ze *zipEntry, opts Opts) (_ []*Article, err error) {
+	defer func() {
+		if e := ze.Close(); e != nil && err == nil {
+			err = e
+		}
+	}()
 
-	zipReader, err := zip.NewReader(bytes.NewReader(content), int64(size))
-	if err != nil {
-		return nil, fmt.Errorf("zip reader error: %w", err)
-	}
+	records := make([]*Article, 0)
 
-	if len(zipReader.File) != 1 {
-		return nil, fmt.Errorf("want 1 file in zip, got %d", len(zipReader.File))
+	r := newArticlesCsvReader(ze)
+	r.url = ze.url
+	for {
+		article, fieldErrs, err := r.read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if err := f.handleParseError(opts, err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		// Malformed subfields are dropped, keeping the rest of the article.
+		for _, fe := range fieldErrs {
+			if err := f.handleParseError(opts, fe); err != nil {
+				return nil, err
+			}
+		}
+		records = append(records, article)
 	}
 
-	records, err := processArticleFile(zipReader.File[0])
-	if err != nil {
-		return nil, err
-	}
 	return records, nil
 }
 
-func processArticleFile(zf *zip.File) (records []*Article, err error) {
-	f, err := zf.Open()
-	if err != nil {
-		return nil, fmt.Errorf("failed to open zip file: %w", err)
-	}
+// readMentions reads all the mentions of the zip entry, closing it.
+func (f *Fetcher) readMentions(ze *zipEntry, opts Opts) (_ []*Mention, err error) {
 	defer func() {
-		if e := f.Close(); e != nil && err == nil {
+		if e := ze.Close(); e != nil && err == nil {
 			err = e
 		}
 	}()
 
-	records = make([]*Article, 0)
+	records := make([]*Mention, 0)
 
-	r := csv.NewReader(f)
-	r.Comma = '\t'
-	r.LazyQuotes = true
-	for i := 0; ; i++ {
-		fields, err := r.Read()
+	r := newMentionsCsvReader(ze)
+	r.url = ze.url
+	for {
+		mention, err := r.read()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			log.Warn().Err(err).Int("row", i).Msg("failed to read GDELT GKG CSV record")
+			if err := f.handleParseError(opts, err); err != nil {
+				return nil, err
+			}
 			continue
 		}
-		a, err := makeArticle(fields)
-		if err != nil {
-			return nil, err
-		}
-		records = append(records, a)
+		records = append(records, mention)
 	}
 
 	return records, nil
 }
 
-func makeArticle(fields []string) (a *Article, err error) {
-	if len(fields) != 27 {
-		return nil, fmt.Errorf("expected 27 CSV columns, actual %d", len(fields))
+// handleParseError reports a malformed record through the configured
+// handler, returning a non-nil error if processing must stop.
+func (f *Fetcher) handleParseError(opts Opts, err error) error {
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		// Not a record-level error, such as a failing read from the network.
+		return err
 	}
-	a = new(Article)
-	a.ID = fields[0]
-	a.DocumentIdentifier = fields[4]
-	a.SharingImage = strings.TrimSpace(fields[18])
-	a.Extras = parseArticleExtras(fields[26])
-	return
-}
-
-var pageTitleRe = regexp.MustCompile(`<PAGE_TITLE>(.*)</PAGE_TITLE>`)
-var spaceRegexp = regexp.MustCompile(`\s`)
-
-func parseArticleExtras(extrasXML string) (ex ArticleExtras) {
-	sm := pageTitleRe.FindStringSubmatch(extrasXML)
-	if len(sm) == 2 {
-		s := html.UnescapeString(sm[1])
-		s = spaceRegexp.ReplaceAllString(s, " ")
-		ex.PageTitle = strings.TrimSpace(s)
+	if opts.ParseErrorHandler == nil {
+		f.logger().Warn("failed to parse GDELT CSV record", "URL", pe.URL, "row", pe.Row, "error", pe)
+		return nil
 	}
-	return
+	return opts.ParseErrorHandler(pe)
 }
 
-func httpGet(url string) (_ []byte, err error) {
-	resp, err := http.Get(url)
+func (f *Fetcher) httpGet(ctx context.Context, url string) (_ []byte, err error) {
+	body, err := f.httpGetBody(ctx, url)
 	if err != nil {
 		return nil, err
 	}
 	defer func() {
-		if e := resp.Body.Close(); e != nil && err == nil {
+		if e := body.Close(); e != nil && err == nil {
 			err = e
 		}
 	}()
-	if resp.StatusCode != http.StatusOK {
-		return nil, NewBadStatusCodeError(resp.StatusCode)
-	}
 
-	bs, err := io.ReadAll(resp.Body)
+	bs, err := io.ReadAll(body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 	return bs, err
 }
 
-func getEventsFromURL(url, md5sum string, size int) ([]*Event, error) {
-	content, err := httpGet(url)
+// httpGetBody performs an HTTP GET request and returns the response body,
+// which must be closed by the caller.
+func (f *Fetcher) httpGetBody(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to HTTP get %q: %w", url, err)
-	}
-
-	if len(content) != size {
-		return nil, fmt.Errorf("expected content size %d, actual %d", size, len(content))
+		return nil, err
 	}
-
-	err = checkMD5Sum(content, md5sum)
+	resp, err := f.client().Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to validate %q: %w", url, err)
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		err := NewBadStatusCodeError(resp.StatusCode)
+		err.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		return nil, err
 	}
+	return resp.Body, nil
+}
 
-	zipReader, err := zip.NewReader(bytes.NewReader(content), int64(size))
-	if err != nil {
-		return nil, fmt.Errorf("zip reader error: %w", err)
+// zipEntry is the single file contained in a downloaded zip archive.
+// Unless the archive comes from a Cache, it is kept in a temporary file,
+// removed on Close.
+type zipEntry struct {
+	io.ReadCloser
+	file      *os.File
+	temporary bool
+	url       string
+	// attempts is the number of download attempts it took.
+	attempts int
+}
+
+func (ze *zipEntry) Close() error {
+	err := ze.ReadCloser.Close()
+	if e := ze.file.Close(); e != nil && err == nil {
+		err = e
+	}
+	if !ze.temporary {
+		return err
 	}
+	if e := os.Remove(ze.file.Name()); e != nil && err == nil {
+		err = e
+	}
+	return err
+}
 
-	if len(zipReader.File) != 1 {
-		return nil, fmt.Errorf("want 1 file in zip, got %d", len(zipReader.File))
+// openZipFile downloads the referenced zip archive to a temporary file,
+// validates its size and MD5 sum, and opens the single file it contains.
+// The download is retried according to the fetcher's RetryPolicy.
+//
+// If the fetcher has a Cache, the archive is read from it when available,
+// and stored into it otherwise.
+func (f *Fetcher) openZipFile(ctx context.Context, fr fileReference) (ze *zipEntry, err error) {
+	if f.Cache != nil {
+		// The size is unknown for GDELT 1.0 files, which are not listed
+		// with it: use the size of the cached copy.
+		if file, size, ok := f.Cache.get(fr); ok {
+			f.logger().Debug("using cached GDELT file", "URL", fr.URL, "batch", fr.Time)
+			return openZipEntry(file, size, fr.URL, false)
+		}
 	}
 
-	records, err := processEventFile(zipReader.File[0])
+	attempts, err := f.retry(ctx, fr.URL, func() (err error) {
+		if err := f.acquireDownloadSlot(ctx); err != nil {
+			return err
+		}
+		defer f.releaseDownloadSlot()
+		ze, err = f.downloadZipFile(ctx, fr)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	return records, nil
+	ze.attempts = attempts
+	return ze, nil
 }
 
-func processEventFile(zf *zip.File) (records []*Event, err error) {
-	f, err := zf.Open()
+func (f *Fetcher) downloadZipFile(ctx context.Context, fr fileReference) (_ *zipEntry, err error) {
+	start := time.Now()
+	body, err := f.httpGetBody(ctx, fr.URL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open zip file: %w", err)
+		return nil, fmt.Errorf("failed to HTTP get %q: %w", fr.URL, err)
 	}
 	defer func() {
-		if e := f.Close(); e != nil && err == nil {
+		if e := body.Close(); e != nil && err == nil {
 			err = e
 		}
 	}()
 
-	records = make([]*Event, 0)
+	// Downloading into the cache directory allows moving the file in place.
+	dir := ""
+	if f.Cache != nil {
+		dir = f.Cache.Dir
+	}
+	tmp, err := os.CreateTemp(dir, "gdelt-*.zip.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	h := md5.New()
+	size, err := io.Copy(io.MultiWriter(tmp, h), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
 
-	r := newEventsCsvReader(f)
-	for i := 0; ; i++ {
-		event, err := r.read()
-		if err == io.EOF {
-			break
+	f.logger().Debug("downloaded GDELT file", "URL", fr.URL, "batch", fr.Time, "size", size, "duration", time.Since(start))
+
+	err = fr.verify(size, h.Sum(nil))
+	if err != nil {
+		return nil, &IntegrityError{URL: fr.URL, Err: err}
+	}
+
+	temporary := true
+	if f.Cache != nil {
+		if err := f.Cache.put(tmp, fr); err != nil {
+			f.logger().Warn("failed to store GDELT file in cache", "URL", fr.URL, "error", err)
+		} else {
+			temporary = false
+			if err := f.Cache.evict(); err != nil {
+				f.logger().Warn("failed to evict GDELT files from cache", "error", err)
+			}
 		}
+	}
+
+	return openZipEntry(tmp, size, fr.URL, temporary)
+}
+
+// openZipEntry opens the single file contained in the zip archive. The
+// archive file is closed on failure, and removed too if temporary.
+func openZipEntry(file *os.File, size int64, url string, temporary bool) (_ *zipEntry, err error) {
+	defer func() {
 		if err != nil {
-			log.Warn().Err(err).Int("row", i).Msg("failed to read GDELT export CSV record")
-			continue
+			_ = file.Close()
+			if temporary {
+				_ = os.Remove(file.Name())
+			}
 		}
-		records = append(records, event)
+	}()
+
+	zipReader, err := zip.NewReader(file, size)
+	if err != nil {
+		return nil, fmt.Errorf("zip reader error: %w", err)
 	}
 
-	return records, nil
+	if len(zipReader.File) != 1 {
+		return nil, fmt.Errorf("want 1 file in zip, got %d", len(zipReader.File))
+	}
+
+	rc, err := zipReader.File[0].Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open zip file: %w", err)
+	}
+	return &zipEntry{ReadCloser: rc, file: file, temporary: temporary, url: url}, nil
 }
 
-func checkMD5Sum(content []byte, expected string) error {
-	actual := fmt.Sprintf("%x", md5.Sum(content))
+func checkMD5Sum(sum []byte, expected string) error {
+	actual := fmt.Sprintf("%x", sum)
 	if actual != expected {
 		return fmt.Errorf("md5 sum: expected %q, actual %q", expected, actual)
 	}
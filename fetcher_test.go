@@ -0,0 +1,400 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gdeltServer serves GDELT 2.0 file lists and zipped batch files from
+// memory, counting the requests of each file.
+type gdeltServer struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	files map[string][]byte
+	hits  map[string]int
+}
+
+func newGDELTServer(t *testing.T) *gdeltServer {
+	s := &gdeltServer{files: make(map[string][]byte), hits: make(map[string]int)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.hits[r.URL.Path]++
+		b, ok := s.files[r.URL.Path]
+		s.mu.Unlock()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(b)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// fetcher returns a Fetcher downloading from the server.
+func (s *gdeltServer) fetcher() *Fetcher {
+	f := NewFetcher(s.Client(), s.URL)
+	f.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	return f
+}
+
+// addBatch serves the files of a batch, and lists them in lastupdate.txt
+// and masterfilelist.txt. Files named in missing are listed, but not
+// served.
+func (s *gdeltServer) addBatch(t *testing.T, tb testBatch, missing ...string) {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var export bytes.Buffer
+	w := NewEventWriter(&export)
+	if err := w.WriteAll(tb.events); err != nil {
+		t.Fatal(err)
+	}
+
+	ts := tb.time.Format(dateAddedTimeLayout)
+	var list strings.Builder
+	for _, f := range []struct{ name, content string }{
+		{ts + ".export.CSV.zip", export.String()},
+		{ts + ".mentions.CSV.zip", strings.Join(tb.mentions, "\n") + "\n"},
+		{ts + ".gkg.csv.zip", strings.Join(tb.articles, "\n") + "\n"},
+	} {
+		z := zipFile(t, strings.TrimSuffix(f.name, ".zip"), f.content)
+		if !slices.Contains(missing, f.name) {
+			s.files["/"+f.name] = z
+		}
+		fmt.Fprintf(&list, "%d %x %s/%s\n", len(z), md5.Sum(z), s.URL, f.name)
+	}
+	s.files["/"+lastUpdateFile] = []byte(list.String())
+	s.files["/"+masterFileListFile] = append(s.files["/"+masterFileListFile], list.String()...)
+}
+
+// zipHits returns the number of requests of the zip files.
+func (s *gdeltServer) zipHits() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hits := make(map[string]int)
+	for path, n := range s.hits {
+		if strings.HasSuffix(path, ".zip") {
+			hits[path] = n
+		}
+	}
+	return hits
+}
+
+func zipFile(t *testing.T, name, content string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create(name)
+	if err == nil {
+		_, err = io.WriteString(w, content)
+	}
+	if err == nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// testBatch holds the content of a batch. Its events, from firstID, are:
+//
+//   - firstID: a fight (root code 19), with two mentions;
+//   - firstID+1: a protest (14), with one mention;
+//   - firstID+2: a public statement (01), with one mention;
+//   - firstID+3: an assault (18) duplicating the URL of firstID.
+//
+// DefaultOpts only accepts firstID and firstID+1.
+type testBatch struct {
+	time     time.Time
+	events   []*Event
+	articles []string
+	mentions []string
+}
+
+func newTestBatch(ts time.Time, firstID uint64) testBatch {
+	url := func(i int) string {
+		return fmt.Sprintf("https://example.com/%d/%c", firstID, 'a'+i)
+	}
+	tb := testBatch{time: ts}
+	for i, root := range []string{"19", "14", "01", "18"} {
+		tb.events = append(tb.events, testEvent(ts, firstID+uint64(i), root, url(i%3)))
+	}
+	for i := 0; i < 3; i++ {
+		tb.articles = append(tb.articles, testArticleRow(ts, i, url(i)))
+	}
+	for i, id := range []uint64{firstID, firstID, firstID + 1, firstID + 2} {
+		tb.mentions = append(tb.mentions, testMentionRow(ts, id, url(int(id-firstID)), i+1))
+	}
+	return tb
+}
+
+func testEvent(ts time.Time, id uint64, rootCode, url string) *Event {
+	day, _ := strconv.Atoi(ts.Format("20060102"))
+	dateAdded, _ := strconv.ParseUint(ts.Format(dateAddedTimeLayout), 10, 64)
+	return &Event{
+		GlobalEventID: id,
+		Day:           day,
+		MonthYear:     day / 100,
+		Year:          day / 10000,
+		FractionDate:  float64(ts.Year()) + float64(ts.YearDay())/365,
+		IsRootEvent:   1,
+		EventCode:     rootCode + "0",
+		EventBaseCode: rootCode + "0",
+		EventRootCode: rootCode,
+		QuadClass:     4,
+		NumMentions:   1,
+		NumSources:    1,
+		NumArticles:   1,
+		DateAdded:     uint64(dateAdded),
+		SourceURL:     url,
+	}
+}
+
+func testArticleRow(ts time.Time, i int, url string) string {
+	f := make([]string, 27)
+	f[0] = fmt.Sprintf("%s-%d", ts.Format(dateAddedTimeLayout), i)
+	f[1] = ts.Format(dateAddedTimeLayout)
+	f[2] = "1"
+	f[3] = "example.com"
+	f[4] = url
+	f[15] = "-1.5,2,3.5,5.5,20,1.2,300"
+	f[26] = "<PAGE_TITLE>Title of " + url + "</PAGE_TITLE>"
+	return strings.Join(f, "\t")
+}
+
+func testMentionRow(ts time.Time, id uint64, url string, sentence int) string {
+	t := ts.Format(dateAddedTimeLayout)
+	return strings.Join([]string{
+		strconv.FormatUint(id, 10), t, t, "1", "example.com", url, strconv.Itoa(sentence),
+		"-1", "10", "20", "1", "50", "1000", "-2.1", "", "",
+	}, "\t")
+}
+
+func eventIDs(events []*Event) []uint64 {
+	ids := make([]uint64, len(events))
+	for i, e := range events {
+		ids[i] = e.GlobalEventID
+	}
+	return ids
+}
+
+var testBatchTime = time.Date(2024, 6, 14, 12, 0, 0, 0, time.UTC)
+
+func TestFetcherFetchLatestEvents(t *testing.T) {
+	s := newGDELTServer(t)
+	s.addBatch(t, newTestBatch(testBatchTime, 100))
+	s.addBatch(t, newTestBatch(testBatchTime.Add(BatchInterval), 200))
+
+	opts := DefaultOpts
+	opts.IncludeMentions = true
+	events, err := s.fetcher().FetchLatestEvents(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := eventIDs(events), []uint64{200, 201}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected events %v, actual %v", want, got)
+	}
+
+	e := events[0]
+	if e.GKGArticle == nil || e.GKGArticle.Extras.PageTitle != "Title of "+e.SourceURL {
+		t.Errorf("event %d: unexpected GKG article %+v", e.GlobalEventID, e.GKGArticle)
+	}
+	for _, e := range events {
+		wantMentions := map[uint64]int{200: 2, 201: 1}[e.GlobalEventID]
+		if len(e.Mentions) != wantMentions {
+			t.Errorf("event %d: expected %d mentions, actual %d", e.GlobalEventID, wantMentions, len(e.Mentions))
+		}
+		for _, m := range e.Mentions {
+			if m.GlobalEventID != e.GlobalEventID {
+				t.Errorf("event %d: attached mention of event %d", e.GlobalEventID, m.GlobalEventID)
+			}
+		}
+	}
+}
+
+func TestFetcherEventsBetween(t *testing.T) {
+	s := newGDELTServer(t)
+	s.addBatch(t, newTestBatch(testBatchTime, 100))
+	// The export file of the second batch is missing: it is skipped.
+	missing := testBatchTime.Add(BatchInterval).Format(dateAddedTimeLayout) + ".export.CSV.zip"
+	s.addBatch(t, newTestBatch(testBatchTime.Add(BatchInterval), 200), missing)
+	s.addBatch(t, newTestBatch(testBatchTime.Add(2*BatchInterval), 300))
+	s.addBatch(t, newTestBatch(testBatchTime.Add(3*BatchInterval), 400))
+
+	ctx := context.Background()
+	f := s.fetcher()
+	start, end := testBatchTime, testBatchTime.Add(3*BatchInterval)
+	want := []uint64{100, 101, 300, 301}
+
+	events, err := f.FetchEventsBetween(ctx, start, end, DefaultOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := eventIDs(events); !reflect.DeepEqual(got, want) {
+		t.Errorf("FetchEventsBetween: expected events %v, actual %v", want, got)
+	}
+
+	it := f.EventsBetween(ctx, start, end, DefaultOpts)
+	defer it.Close()
+	var got []uint64
+	for it.Next() {
+		got = append(got, it.Event().GlobalEventID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EventsBetween: expected events %v, actual %v", want, got)
+	}
+}
+
+func TestFetcherRawBatchesBetween(t *testing.T) {
+	s := newGDELTServer(t)
+	s.addBatch(t, newTestBatch(testBatchTime, 100))
+	missing := testBatchTime.Add(BatchInterval).Format(dateAddedTimeLayout) + ".gkg.csv.zip"
+	s.addBatch(t, newTestBatch(testBatchTime.Add(BatchInterval), 200), missing)
+	s.addBatch(t, newTestBatch(testBatchTime.Add(2*BatchInterval), 300))
+
+	batches, err := s.fetcher().FetchRawBatchesBetween(context.Background(), testBatchTime, testBatchTime.Add(time.Hour), Opts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batches) != 2 {
+		t.Fatalf("expected 2 batches, actual %d", len(batches))
+	}
+	for i, b := range batches {
+		firstID := uint64(100 + 200*i)
+		if want := testBatchTime.Add(time.Duration(2*i) * BatchInterval); !b.Time.Equal(want) {
+			t.Errorf("batch %d: expected time %v, actual %v", i, want, b.Time)
+		}
+		if got, want := eventIDs(b.Events), []uint64{firstID, firstID + 1, firstID + 2, firstID + 3}; !reflect.DeepEqual(got, want) {
+			t.Errorf("batch %d: expected events %v, actual %v", i, want, got)
+		}
+		if len(b.Articles) != 3 || len(b.Mentions) != 4 {
+			t.Errorf("batch %d: expected 3 articles and 4 mentions, actual %d and %d", i, len(b.Articles), len(b.Mentions))
+		}
+		url := b.Events[0].SourceURL
+		if got := eventIDs(b.EventsByURL[url]); !reflect.DeepEqual(got, []uint64{firstID, firstID + 3}) {
+			t.Errorf("batch %d: unexpected events of %s: %v", i, url, got)
+		}
+		if a := b.ArticlesByURL[url]; len(a) != 1 || a[0].DocumentIdentifier != url {
+			t.Errorf("batch %d: unexpected articles of %s: %v", i, url, a)
+		}
+		if n := len(b.MentionsByEventID[firstID]); n != 2 {
+			t.Errorf("batch %d: expected 2 mentions of event %d, actual %d", i, firstID, n)
+		}
+	}
+}
+
+func TestFetcherCache(t *testing.T) {
+	s := newGDELTServer(t)
+	s.addBatch(t, newTestBatch(testBatchTime, 100))
+
+	cache, err := NewCache(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := s.fetcher()
+	f.Cache = cache
+	opts := DefaultOpts
+	opts.IncludeMentions = true
+
+	ctx := context.Background()
+	first, err := f.FetchLatestEvents(ctx, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := f.FetchLatestEvents(ctx, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("cached events differ: %v, %v", eventIDs(first), eventIDs(second))
+	}
+
+	hits := s.zipHits()
+	if len(hits) != 3 {
+		t.Errorf("expected 3 zip files downloaded, actual %v", hits)
+	}
+	for path, n := range hits {
+		if n != 1 {
+			t.Errorf("%s downloaded %d times, expected once", path, n)
+		}
+	}
+}
+
+func TestFetcherCacheEviction(t *testing.T) {
+	s := newGDELTServer(t)
+	s.addBatch(t, newTestBatch(testBatchTime, 100))
+
+	dir := t.TempDir()
+	cache, err := NewCache(dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := s.fetcher()
+	f.Cache = cache
+	opts := DefaultOpts
+	opts.IncludeMentions = true
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		events, err := f.FetchLatestEvents(ctx, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := eventIDs(events); !reflect.DeepEqual(got, []uint64{100, 101}) {
+			t.Errorf("fetch %d: unexpected events %v", i+1, got)
+		}
+		files, err := filepath.Glob(filepath.Join(dir, "*"+cacheFileExt))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(files) != 1 {
+			t.Errorf("fetch %d: expected 1 file left in the cache, actual %d", i+1, len(files))
+		}
+	}
+
+	// Only the most recently used file survives eviction, so at least two
+	// files were downloaded again.
+	var total int
+	for _, n := range s.zipHits() {
+		total += n
+	}
+	if total < 5 {
+		t.Errorf("expected at least 5 downloads, actual %d", total)
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"math"
+	"net/url"
+	"strings"
+)
+
+// Filter reports whether an event should be kept.
+//
+// Filters can be combined with And, Or and Not, and are applied through
+// Opts.Filter.
+type Filter func(ev *Event) bool
+
+// And returns a Filter keeping the events kept by all the filters.
+func And(filters ...Filter) Filter {
+	return func(ev *Event) bool {
+		for _, f := range filters {
+			if !f(ev) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or returns a Filter keeping the events kept by any of the filters.
+func Or(filters ...Filter) Filter {
+	return func(ev *Event) bool {
+		for _, f := range filters {
+			if f(ev) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns a Filter keeping the events discarded by f.
+func Not(f Filter) Filter {
+	return func(ev *Event) bool {
+		return !f(ev)
+	}
+}
+
+// ActorCountryIn keeps the events where Actor1 or Actor2 has one of the
+// given CAMEO country codes.
+func ActorCountryIn(codes ...string) Filter {
+	set := stringSet(codes)
+	return func(ev *Event) bool {
+		return set[ev.Actor1.CountryCode] || set[ev.Actor2.CountryCode]
+	}
+}
+
+// ActorTypeIn keeps the events where Actor1 or Actor2 has one of the given
+// CAMEO type codes, among any of its three type codes.
+func ActorTypeIn(codes ...string) Filter {
+	set := stringSet(codes)
+	hasType := func(a *ActorData) bool {
+		return set[a.Type1Code] || set[a.Type2Code] || set[a.Type3Code]
+	}
+	return func(ev *Event) bool {
+		return hasType(&ev.Actor1) || hasType(&ev.Actor2)
+	}
+}
+
+// ActionCountryIn keeps the events whose ActionGeo has one of the given
+// FIPS10-4 country codes.
+func ActionCountryIn(codes ...string) Filter {
+	set := stringSet(codes)
+	return func(ev *Event) bool {
+		return set[ev.ActionGeo.CountryCode]
+	}
+}
+
+// ActionADM1In keeps the events whose ActionGeo has one of the given ADM1
+// codes (country code followed by the administrative division code).
+func ActionADM1In(codes ...string) Filter {
+	set := stringSet(codes)
+	return func(ev *Event) bool {
+		return set[ev.ActionGeo.ADM1Code]
+	}
+}
+
+// ActionInBoundingBox keeps the events whose ActionGeo lies within the
+// given latitude and longitude bounds, inclusive.
+func ActionInBoundingBox(minLat, minLong, maxLat, maxLong float64) Filter {
+	return func(ev *Event) bool {
+		g := &ev.ActionGeo
+		if !g.Lat.Valid || !g.Long.Valid {
+			return false
+		}
+		return g.Lat.Float64 >= minLat && g.Lat.Float64 <= maxLat &&
+			g.Long.Float64 >= minLong && g.Long.Float64 <= maxLong
+	}
+}
+
+// ActionWithinRadius keeps the events whose ActionGeo is at most km
+// kilometres away from the given point.
+func ActionWithinRadius(lat, long, km float64) Filter {
+	return func(ev *Event) bool {
+		g := &ev.ActionGeo
+		if !g.Lat.Valid || !g.Long.Valid {
+			return false
+		}
+		return haversine(lat, long, g.Lat.Float64, g.Long.Float64) <= km
+	}
+}
+
+// QuadClassIn keeps the events with one of the given QuadClass values.
+func QuadClassIn(classes ...int) Filter {
+	return func(ev *Event) bool {
+		for _, c := range classes {
+			if ev.QuadClass == c {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// GoldsteinBetween keeps the events whose GoldsteinScale is between min
+// and max, inclusive. Events without a score are discarded.
+func GoldsteinBetween(min, max float64) Filter {
+	return func(ev *Event) bool {
+		g := ev.GoldsteinScale
+		return g.Valid && g.Float64 >= min && g.Float64 <= max
+	}
+}
+
+// AvgToneBetween keeps the events whose AvgTone is between min and max,
+// inclusive.
+func AvgToneBetween(min, max float64) Filter {
+	return func(ev *Event) bool {
+		return ev.AvgTone >= min && ev.AvgTone <= max
+	}
+}
+
+// MinMentions keeps the events with at least n mentions.
+func MinMentions(n int) Filter {
+	return func(ev *Event) bool {
+		return ev.NumMentions >= n
+	}
+}
+
+// MinSources keeps the events with at least n sources.
+func MinSources(n int) Filter {
+	return func(ev *Event) bool {
+		return ev.NumSources >= n
+	}
+}
+
+// RootEventOnly keeps the events found in the lead paragraph of a document.
+func RootEventOnly() Filter {
+	return func(ev *Event) bool {
+		return ev.IsRootEvent == 1
+	}
+}
+
+// SourceDomainIn keeps the events whose SourceURL belongs to one of the
+// given domains or to a subdomain of them, such as "bbc.co.uk".
+func SourceDomainIn(domains ...string) Filter {
+	return func(ev *Event) bool {
+		u, err := url.Parse(ev.SourceURL)
+		if err != nil {
+			return false
+		}
+		host := strings.ToLower(u.Hostname())
+		for _, d := range domains {
+			d = strings.ToLower(d)
+			if host == d || strings.HasSuffix(host, "."+d) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func stringSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+// earthRadius is the mean radius of the Earth, in kilometres.
+const earthRadius = 6371.0
+
+// haversine returns the great-circle distance in kilometres between two
+// points given in degrees.
+func haversine(lat1, long1, lat2, long2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLong := (long2 - long1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLong/2)*math.Sin(dLong/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
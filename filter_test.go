@@ -0,0 +1,197 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"math"
+	"testing"
+)
+
+// actionAt returns an event whose ActionGeo lies at the given coordinates.
+func actionAt(lat, long float64) *Event {
+	return &Event{ActionGeo: GeoData{
+		Lat:  NullableFloat64{Float64: lat, Valid: true},
+		Long: NullableFloat64{Float64: long, Valid: true},
+	}}
+}
+
+func TestHaversine(t *testing.T) {
+	tests := []struct {
+		name                     string
+		lat1, long1, lat2, long2 float64
+		expected                 float64
+	}{
+		{"same point", 41.9, 12.5, 41.9, 12.5, 0},
+		{"one degree of latitude", 0, 0, 1, 0, earthRadius * math.Pi / 180},
+		{"one degree of longitude at the equator", 0, 0, 0, 1, earthRadius * math.Pi / 180},
+		{"antipodes", 0, 0, 0, 180, earthRadius * math.Pi},
+		{"across the antimeridian", 0, 179.5, 0, -179.5, earthRadius * math.Pi / 180},
+		{"Paris to London", 48.8566, 2.3522, 51.5074, -0.1278, 343.56},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := haversine(tt.lat1, tt.long1, tt.lat2, tt.long2)
+			if math.Abs(d-tt.expected) > 0.01 {
+				t.Errorf("expected %.3f km, actual %.3f km", tt.expected, d)
+			}
+		})
+	}
+}
+
+func TestActionWithinRadius(t *testing.T) {
+	oneDegree := earthRadius * math.Pi / 180
+	tests := []struct {
+		name     string
+		long     float64
+		ev       *Event
+		km       float64
+		expected bool
+	}{
+		{"center", 0, actionAt(0, 0), 0, true},
+		{"inside", 0, actionAt(1, 0), oneDegree + 0.001, true},
+		{"outside", 0, actionAt(1, 0), oneDegree - 0.001, false},
+		{"across the antimeridian", 179.5, actionAt(0, -179.5), oneDegree + 0.001, true},
+		{"no coordinates", 0, &Event{}, 1e6, false},
+		{"no longitude", 0, &Event{ActionGeo: GeoData{Lat: NullableFloat64{Valid: true}}}, 1e6, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := ActionWithinRadius(0, tt.long, tt.km)(tt.ev); actual != tt.expected {
+				t.Errorf("expected %v, actual %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestActionInBoundingBox(t *testing.T) {
+	f := ActionInBoundingBox(35, 6, 47, 19)
+	tests := []struct {
+		name     string
+		ev       *Event
+		expected bool
+	}{
+		{"inside", actionAt(41.9, 12.5), true},
+		{"min corner", actionAt(35, 6), true},
+		{"max corner", actionAt(47, 19), true},
+		{"below", actionAt(34.999, 12), false},
+		{"above", actionAt(47.001, 12), false},
+		{"west", actionAt(40, 5.999), false},
+		{"east", actionAt(40, 19.001), false},
+		{"no coordinates", &Event{}, false},
+		{"no latitude", &Event{ActionGeo: GeoData{Long: NullableFloat64{Float64: 12, Valid: true}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := f(tt.ev); actual != tt.expected {
+				t.Errorf("expected %v, actual %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFilterCombinators(t *testing.T) {
+	yes := func(*Event) bool { return true }
+	no := func(*Event) bool { return false }
+	tests := []struct {
+		name     string
+		f        Filter
+		expected bool
+	}{
+		{"And()", And(), true},
+		{"And(yes)", And(yes), true},
+		{"And(yes, no)", And(yes, no), false},
+		{"Or()", Or(), false},
+		{"Or(no)", Or(no), false},
+		{"Or(no, yes)", Or(no, yes), true},
+		{"Not(yes)", Not(yes), false},
+		{"Not(no)", Not(no), true},
+		{"Not(And())", Not(And()), false},
+		{"Not(Or())", Not(Or()), true},
+	}
+	ev := &Event{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := tt.f(ev); actual != tt.expected {
+				t.Errorf("expected %v, actual %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFilterPredicates(t *testing.T) {
+	ev := &Event{
+		Actor1:         ActorData{CountryCode: "RUS", Type1Code: "GOV"},
+		Actor2:         ActorData{CountryCode: "UKR", Type3Code: "MIL"},
+		ActionGeo:      GeoData{CountryCode: "UP", ADM1Code: "UP14"},
+		QuadClass:      4,
+		GoldsteinScale: NullableFloat64{Float64: -10, Valid: true},
+		AvgTone:        -3.5,
+		NumMentions:    10,
+		NumSources:     2,
+		IsRootEvent:    1,
+		SourceURL:      "https://www.News.BBC.co.uk/world?id=1",
+	}
+	tests := []struct {
+		name     string
+		f        Filter
+		expected bool
+	}{
+		{"ActorCountryIn actor1", ActorCountryIn("RUS"), true},
+		{"ActorCountryIn actor2", ActorCountryIn("USA", "UKR"), true},
+		{"ActorCountryIn none", ActorCountryIn("USA"), false},
+		{"ActorCountryIn no codes", ActorCountryIn(), false},
+		{"ActorTypeIn type1", ActorTypeIn("GOV"), true},
+		{"ActorTypeIn type3", ActorTypeIn("MIL"), true},
+		{"ActorTypeIn none", ActorTypeIn("REB"), false},
+		{"ActionCountryIn", ActionCountryIn("UP"), true},
+		{"ActionCountryIn CAMEO code", ActionCountryIn("UKR"), false},
+		{"ActionADM1In", ActionADM1In("UP14"), true},
+		{"ActionADM1In none", ActionADM1In("UP"), false},
+		{"QuadClassIn", QuadClassIn(3, 4), true},
+		{"QuadClassIn none", QuadClassIn(1, 2), false},
+		{"QuadClassIn no classes", QuadClassIn(), false},
+		{"GoldsteinBetween lower bound", GoldsteinBetween(-10, 0), true},
+		{"GoldsteinBetween upper bound", GoldsteinBetween(-20, -10), true},
+		{"GoldsteinBetween outside", GoldsteinBetween(-9.9, 10), false},
+		{"AvgToneBetween bounds", AvgToneBetween(-3.5, -3.5), true},
+		{"AvgToneBetween outside", AvgToneBetween(-3.4, 0), false},
+		{"MinMentions equal", MinMentions(10), true},
+		{"MinMentions above", MinMentions(11), false},
+		{"MinSources equal", MinSources(2), true},
+		{"MinSources above", MinSources(3), false},
+		{"RootEventOnly", RootEventOnly(), true},
+		{"SourceDomainIn subdomain", SourceDomainIn("bbc.co.uk"), true},
+		{"SourceDomainIn case", SourceDomainIn("NEWS.bbc.CO.UK"), true},
+		{"SourceDomainIn suffix only", SourceDomainIn("c.co.uk"), false},
+		{"SourceDomainIn other", SourceDomainIn("cnn.com"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := tt.f(ev); actual != tt.expected {
+				t.Errorf("expected %v, actual %v", tt.expected, actual)
+			}
+		})
+	}
+
+	if GoldsteinBetween(-10, 10)(&Event{}) {
+		t.Error("expected an event without GoldsteinScale to be discarded")
+	}
+	if RootEventOnly()(&Event{IsRootEvent: 0}) {
+		t.Error("expected a non-root event to be discarded")
+	}
+	if SourceDomainIn("bbc.co.uk")(&Event{SourceURL: "://bbc.co.uk"}) {
+		t.Error("expected an invalid SourceURL to be discarded")
+	}
+}
@@ -0,0 +1,602 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParseFilter compiles a textual filter expression into a Filter, such as:
+//
+//	ActionGeo.CountryCode in ("UP", "RS") and QuadClass = 4 and GoldsteinScale < -5
+//
+// An expression compares Event fields, named by their dot-separated path
+// (for example Actor1.Type1Code or GKGArticle.Extras.PageTitle), with
+// string, number or boolean literals. The comparison operators are =, !=,
+// <, <=, >, >=, in (...), not in (...) and, for strings only, contains.
+// Comparisons can be combined with and, or, not and parentheses.
+//
+// GeoType and MentionType fields can be compared with their names as well
+// as their numeric codes, for example ActionGeo.Type = "WORLDCITY".
+//
+// Fields are type-checked against the Event struct. Comparisons involving a
+// missing value, such as a nil GKGArticle or a null GoldsteinScale, are
+// always false.
+func ParseFilter(expr string) (Filter, error) {
+	toks, err := lexFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{expr: expr, toks: toks}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t.pos, "unexpected %s", t)
+	}
+	return f, nil
+}
+
+// FilterExprError is returned by ParseFilter for invalid expressions.
+type FilterExprError struct {
+	Expr string
+	// Pos is the 0-based byte offset of the error within Expr.
+	Pos int
+	Msg string
+}
+
+func (e *FilterExprError) Error() string {
+	return fmt.Sprintf("invalid filter expression at position %d: %s", e.Pos+1, e.Msg)
+}
+
+type tokenKind uint8
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+	tokDot
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return "string " + t.text
+	default:
+		return strconv.Quote(t.text)
+	}
+}
+
+func lexFilter(expr string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			toks = append(toks, token{tokLParen, "(", i})
+			i++
+		case c == ')':
+			toks = append(toks, token{tokRParen, ")", i})
+			i++
+		case c == ',':
+			toks = append(toks, token{tokComma, ",", i})
+			i++
+		case c == '.':
+			toks = append(toks, token{tokDot, ".", i})
+			i++
+		case c == '"':
+			j := i + 1
+			for ; j < len(expr) && expr[j] != '"'; j++ {
+				if expr[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(expr) {
+				return nil, &FilterExprError{Expr: expr, Pos: i, Msg: "unterminated string"}
+			}
+			if _, err := strconv.Unquote(expr[i : j+1]); err != nil {
+				return nil, &FilterExprError{Expr: expr, Pos: i, Msg: "invalid string " + expr[i:j+1]}
+			}
+			toks = append(toks, token{tokString, expr[i : j+1], i})
+			i = j + 1
+		case c == '=' || c == '!' || c == '<' || c == '>':
+			j := i + 1
+			if j < len(expr) && expr[j] == '=' {
+				j++
+			}
+			op := expr[i:j]
+			if op == "!" {
+				return nil, &FilterExprError{Expr: expr, Pos: i, Msg: `unexpected "!", use "!=" or "not"`}
+			}
+			toks = append(toks, token{tokOp, op, i})
+			i = j
+		case c == '-' || c == '+' || (c >= '0' && c <= '9'):
+			j := i + 1
+			for j < len(expr) && (isNumberChar(expr[j]) || ((expr[j] == '-' || expr[j] == '+') && (expr[j-1] == 'e' || expr[j-1] == 'E'))) {
+				j++
+			}
+			toks = append(toks, token{tokNumber, expr[i:j], i})
+			i = j
+		case c == '_' || unicode.IsLetter(rune(c)):
+			j := i + 1
+			for j < len(expr) && (expr[j] == '_' || unicode.IsLetter(rune(expr[j])) || unicode.IsDigit(rune(expr[j]))) {
+				j++
+			}
+			toks = append(toks, token{tokIdent, expr[i:j], i})
+			i = j
+		default:
+			return nil, &FilterExprError{Expr: expr, Pos: i, Msg: fmt.Sprintf("unexpected character %q", c)}
+		}
+	}
+	return append(toks, token{tokEOF, "", len(expr)}), nil
+}
+
+func isNumberChar(c byte) bool {
+	return (c >= '0' && c <= '9') || c == '.' || c == 'e' || c == 'E'
+}
+
+type filterParser struct {
+	expr string
+	toks []token
+	i    int
+}
+
+func (p *filterParser) peek() token {
+	return p.toks[p.i]
+}
+
+func (p *filterParser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *filterParser) errorf(pos int, format string, a ...any) error {
+	return &FilterExprError{Expr: p.expr, Pos: pos, Msg: fmt.Sprintf(format, a...)}
+}
+
+func isKeyword(t token, kw string) bool {
+	return t.kind == tokIdent && strings.EqualFold(t.text, kw)
+}
+
+func (p *filterParser) parseOr() (Filter, error) {
+	f, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	filters := []Filter{f}
+	for isKeyword(p.peek(), "or") {
+		p.next()
+		f, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	if len(filters) == 1 {
+		return filters[0], nil
+	}
+	return Or(filters...), nil
+}
+
+func (p *filterParser) parseAnd() (Filter, error) {
+	f, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	filters := []Filter{f}
+	for isKeyword(p.peek(), "and") {
+		p.next()
+		f, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	if len(filters) == 1 {
+		return filters[0], nil
+	}
+	return And(filters...), nil
+}
+
+func (p *filterParser) parseUnary() (Filter, error) {
+	t := p.peek()
+	switch {
+	case isKeyword(t, "not"):
+		p.next()
+		f, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not(f), nil
+	case t.kind == tokLParen:
+		p.next()
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokRParen {
+			return nil, p.errorf(t.pos, "expected \")\", found %s", t)
+		}
+		return f, nil
+	case t.kind == tokIdent:
+		return p.parseComparison()
+	default:
+		return nil, p.errorf(t.pos, "expected a field name, found %s", t)
+	}
+}
+
+func (p *filterParser) parseComparison() (Filter, error) {
+	field, err := p.parseField()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.next()
+	switch {
+	case isKeyword(t, "in"):
+		return p.parseIn(field, false)
+	case isKeyword(t, "not"):
+		if in := p.next(); !isKeyword(in, "in") {
+			return nil, p.errorf(in.pos, "expected \"in\" after \"not\", found %s", in)
+		}
+		return p.parseIn(field, true)
+	case isKeyword(t, "contains"):
+		if field.kind != reflect.String {
+			return nil, p.errorf(t.pos, "contains requires a string field, but %s is %s", field.name, field.typeName)
+		}
+		lit, err := p.parseLiteral(field)
+		if err != nil {
+			return nil, err
+		}
+		return func(ev *Event) bool {
+			v, ok := field.value(ev)
+			return ok && strings.Contains(v.String(), lit.s)
+		}, nil
+	case t.kind == tokOp:
+		op := t.text
+		if (op == "<" || op == "<=" || op == ">" || op == ">=") && field.kind == reflect.Bool {
+			return nil, p.errorf(t.pos, "operator %s cannot be applied to boolean field %s", op, field.name)
+		}
+		lit, err := p.parseLiteral(field)
+		if err != nil {
+			return nil, err
+		}
+		match := comparisonMatcher(op)
+		return func(ev *Event) bool {
+			v, ok := field.value(ev)
+			return ok && match(field.compare(v, lit))
+		}, nil
+	default:
+		return nil, p.errorf(t.pos, "expected a comparison operator after %s, found %s", field.name, t)
+	}
+}
+
+func comparisonMatcher(op string) func(cmp int) bool {
+	switch op {
+	case "=", "==":
+		return func(cmp int) bool { return cmp == 0 }
+	case "!=":
+		return func(cmp int) bool { return cmp != 0 }
+	case "<":
+		return func(cmp int) bool { return cmp < 0 }
+	case "<=":
+		return func(cmp int) bool { return cmp <= 0 }
+	case ">":
+		return func(cmp int) bool { return cmp > 0 }
+	default: // ">="
+		return func(cmp int) bool { return cmp >= 0 }
+	}
+}
+
+func (p *filterParser) parseIn(field *filterField, negate bool) (Filter, error) {
+	if t := p.next(); t.kind != tokLParen {
+		return nil, p.errorf(t.pos, "expected \"(\" after in, found %s", t)
+	}
+	var lits []filterLiteral
+	for {
+		lit, err := p.parseLiteral(field)
+		if err != nil {
+			return nil, err
+		}
+		lits = append(lits, lit)
+		t := p.next()
+		if t.kind == tokRParen {
+			break
+		}
+		if t.kind != tokComma {
+			return nil, p.errorf(t.pos, "expected \",\" or \")\", found %s", t)
+		}
+	}
+	return func(ev *Event) bool {
+		v, ok := field.value(ev)
+		if !ok {
+			return false
+		}
+		for _, lit := range lits {
+			if field.compare(v, lit) == 0 {
+				return !negate
+			}
+		}
+		return negate
+	}, nil
+}
+
+// filterField is a resolved Event field path.
+type filterField struct {
+	name     string
+	typeName string
+	// steps are the field indexes to follow from Event, dereferencing
+	// pointers along the way.
+	steps    [][]int
+	nullable bool
+	kind     reflect.Kind
+	// enum is set for fields that can also be compared with names.
+	enum *filterEnum
+}
+
+// filterEnum describes an enumeration type whose values can be written as
+// string literals.
+type filterEnum struct {
+	parse func(name string) (uint64, bool)
+	names []string
+}
+
+// filterEnums are the enumeration types of the Event fields.
+var filterEnums = map[reflect.Type]*filterEnum{
+	reflect.TypeOf(GeoType(0)): {
+		parse: func(name string) (uint64, bool) {
+			g, ok := GeoTypeFromString(name)
+			return uint64(g), ok
+		},
+		names: enumNames(Country, WorldState),
+	},
+	reflect.TypeOf(MentionType(0)): {
+		parse: func(name string) (uint64, bool) {
+			t, ok := MentionTypeFromString(name)
+			return uint64(t), ok
+		},
+		names: enumNames(WebMention, NonTextualSourceMention),
+	},
+}
+
+// enumNames returns the names of the enumeration values from first to last.
+func enumNames[T interface {
+	~uint8
+	String() string
+}](first, last T) []string {
+	var names []string
+	for v := first; v <= last; v++ {
+		names = append(names, v.String())
+	}
+	return names
+}
+
+var (
+	eventType           = reflect.TypeOf(Event{})
+	nullableFloat64Type = reflect.TypeOf(NullableFloat64{})
+)
+
+func (p *filterParser) parseField() (*filterField, error) {
+	field := &filterField{}
+	t := eventType
+	var names []string
+	for {
+		tok := p.next()
+		if tok.kind != tokIdent {
+			return nil, p.errorf(tok.pos, "expected a field name, found %s", tok)
+		}
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct || t == nullableFloat64Type {
+			return nil, p.errorf(tok.pos, "%s is %s and has no field %s", strings.Join(names, "."), t, tok.text)
+		}
+		sf, ok := t.FieldByName(tok.text)
+		if !ok || !sf.IsExported() {
+			return nil, p.errorf(tok.pos, "unknown field %s", fieldSuggestion(t, names, tok.text))
+		}
+		names = append(names, sf.Name)
+		field.steps = append(field.steps, sf.Index)
+		t = sf.Type
+		if p.peek().kind != tokDot {
+			break
+		}
+		p.next()
+	}
+	field.name = strings.Join(names, ".")
+	field.typeName = t.String()
+	field.enum = filterEnums[t]
+
+	if t == nullableFloat64Type {
+		field.nullable = true
+		field.kind = reflect.Float64
+		return field, nil
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		field.kind = t.Kind()
+		return field, nil
+	case reflect.Struct, reflect.Pointer:
+		st := t
+		if st.Kind() == reflect.Pointer {
+			st = st.Elem()
+		}
+		return nil, p.errorf(p.peek().pos, "%s is a struct, select one of its fields: %s", field.name, strings.Join(exportedFields(st), ", "))
+	default:
+		return nil, p.errorf(p.peek().pos, "field %s of type %s cannot be compared", field.name, t)
+	}
+}
+
+func fieldSuggestion(t reflect.Type, parent []string, name string) string {
+	full := strings.Join(append(parent[:len(parent):len(parent)], name), ".")
+	fields := exportedFields(t)
+	for _, f := range fields {
+		if strings.EqualFold(f, name) {
+			return fmt.Sprintf("%s, did you mean %s?", full, f)
+		}
+	}
+	return fmt.Sprintf("%s, valid fields are: %s", full, strings.Join(fields, ", "))
+}
+
+func exportedFields(t reflect.Type) []string {
+	var names []string
+	for _, f := range reflect.VisibleFields(t) {
+		if f.IsExported() && !f.Anonymous {
+			names = append(names, f.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// value returns the value of the field for the given event, or false if
+// it is missing.
+func (f *filterField) value(ev *Event) (reflect.Value, bool) {
+	v := reflect.ValueOf(ev).Elem()
+	for _, index := range f.steps {
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.FieldByIndex(index)
+	}
+	if f.nullable {
+		nf := v.Interface().(NullableFloat64)
+		if !nf.Valid {
+			return reflect.Value{}, false
+		}
+		return reflect.ValueOf(nf.Float64), true
+	}
+	return v, true
+}
+
+// filterLiteral holds a literal value converted to the type of the field
+// it is compared with.
+type filterLiteral struct {
+	s string
+	b bool
+	i int64
+	u uint64
+	f float64
+}
+
+func (p *filterParser) parseLiteral(field *filterField) (lit filterLiteral, err error) {
+	t := p.next()
+	mismatch := func() error {
+		return p.errorf(t.pos, "cannot compare %s field %s with %s", field.typeName, field.name, t)
+	}
+	switch field.kind {
+	case reflect.String:
+		if t.kind != tokString {
+			return lit, mismatch()
+		}
+		lit.s, _ = strconv.Unquote(t.text)
+	case reflect.Bool:
+		if !isKeyword(t, "true") && !isKeyword(t, "false") {
+			return lit, mismatch()
+		}
+		lit.b = isKeyword(t, "true")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if t.kind != tokNumber {
+			return lit, mismatch()
+		}
+		if lit.i, err = strconv.ParseInt(t.text, 10, 64); err != nil {
+			return lit, p.errorf(t.pos, "%s is not a valid integer for field %s", t.text, field.name)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if t.kind == tokString && field.enum != nil {
+			name, _ := strconv.Unquote(t.text)
+			var ok bool
+			if lit.u, ok = field.enum.parse(strings.ToUpper(name)); !ok || len(name) == 0 {
+				return lit, p.errorf(t.pos, "%s is not a valid %s, valid names are: %s", t.text, field.typeName, strings.Join(field.enum.names, ", "))
+			}
+			return lit, nil
+		}
+		if t.kind != tokNumber {
+			return lit, mismatch()
+		}
+		if lit.u, err = strconv.ParseUint(t.text, 10, 64); err != nil {
+			return lit, p.errorf(t.pos, "%s is not a valid non-negative integer for field %s", t.text, field.name)
+		}
+	default: // floats
+		if t.kind != tokNumber {
+			return lit, mismatch()
+		}
+		if lit.f, err = strconv.ParseFloat(t.text, 64); err != nil {
+			return lit, p.errorf(t.pos, "%s is not a valid number", t.text)
+		}
+	}
+	return lit, nil
+}
+
+// compare returns -1, 0 or +1 if the field value is respectively less
+// than, equal to, or greater than the literal.
+func (f *filterField) compare(v reflect.Value, lit filterLiteral) int {
+	switch f.kind {
+	case reflect.String:
+		return strings.Compare(v.String(), lit.s)
+	case reflect.Bool:
+		if v.Bool() == lit.b {
+			return 0
+		}
+		return 1
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compareOrdered(v.Int(), lit.i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return compareOrdered(v.Uint(), lit.u)
+	default:
+		return compareOrdered(v.Float(), lit.f)
+	}
+}
+
+func compareOrdered[T int64 | uint64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
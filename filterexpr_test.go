@@ -0,0 +1,185 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseFilterErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		pos  int
+		msg  string
+	}{
+		{`QuadClass =`, 11, "cannot compare int field QuadClass with end of expression"},
+		{`Foo = 1`, 0, "unknown field Foo, valid fields are: "},
+		{`quadclass = 1`, 0, "unknown field quadclass, did you mean QuadClass?"},
+		{`ActionGeo.Lat.Foo = 1`, 14, "ActionGeo.Lat is gdelt.NullableFloat64 and has no field Foo"},
+		{`QuadClass = "4"`, 12, `cannot compare int field QuadClass with string "4"`},
+		{`QuadClass < 1.5`, 12, "1.5 is not a valid integer for field QuadClass"},
+		{`GlobalEventID = -1`, 16, "-1 is not a valid non-negative integer for field GlobalEventID"},
+		{`ActionGeo = 1`, 10, "ActionGeo is a struct, select one of its fields: "},
+		{`Mentions = 1`, 9, "field Mentions of type []*gdelt.Mention cannot be compared"},
+		{`QuadClass contains 4`, 10, "contains requires a string field, but QuadClass is int"},
+		{`SourceURL = "abc`, 12, "unterminated string"},
+		{`QuadClass ! 4`, 10, `unexpected "!", use "!=" or "not"`},
+		{`QuadClass # 4`, 10, `unexpected character '#'`},
+		{`(QuadClass = 4`, 14, `expected ")", found end of expression`},
+		{`QuadClass = 4 IsRootEvent = 1`, 14, `unexpected "IsRootEvent"`},
+		{`QuadClass not 4`, 14, `expected "in" after "not", found "4"`},
+		{`QuadClass in 4`, 13, `expected "(" after in, found "4"`},
+		{`QuadClass in (1 2)`, 16, `expected "," or ")", found "2"`},
+		{`and QuadClass = 4`, 0, "unknown field and, valid fields are: "},
+		{`QuadClass = 4 or`, 16, "expected a field name, found end of expression"},
+		{`ActionGeo.Type = "CITY"`, 17, `"CITY" is not a valid gdelt.GeoType, valid names are: COUNTRY, USSTATE, USCITY, WORLDCITY, WORLDSTATE`},
+		{`ActionGeo.Type = ""`, 17, `"" is not a valid gdelt.GeoType`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseFilter(tt.expr)
+			var fe *FilterExprError
+			if !errors.As(err, &fe) {
+				t.Fatalf("expected *FilterExprError, actual %v", err)
+			}
+			if fe.Pos != tt.pos {
+				t.Errorf("expected position %d, actual %d (%v)", tt.pos, fe.Pos, err)
+			}
+			if !strings.Contains(fe.Msg, tt.msg) {
+				t.Errorf("expected message containing %q, actual %q", tt.msg, fe.Msg)
+			}
+		})
+	}
+}
+
+func TestParseFilterPrecedence(t *testing.T) {
+	events := make([]*Event, 0, 8)
+	for quadClass := 1; quadClass <= 4; quadClass++ {
+		for isRoot := 0; isRoot <= 1; isRoot++ {
+			events = append(events, &Event{QuadClass: quadClass, IsRootEvent: isRoot})
+		}
+	}
+	a := func(ev *Event) bool { return ev.QuadClass == 1 }
+	b := func(ev *Event) bool { return ev.QuadClass == 2 }
+	c := func(ev *Event) bool { return ev.IsRootEvent == 1 }
+
+	tests := []struct {
+		expr string
+		want Filter
+	}{
+		{`QuadClass = 1 or QuadClass = 2 and IsRootEvent = 1`,
+			func(ev *Event) bool { return a(ev) || (b(ev) && c(ev)) }},
+		{`QuadClass = 2 and IsRootEvent = 1 or QuadClass = 1`,
+			func(ev *Event) bool { return (b(ev) && c(ev)) || a(ev) }},
+		{`(QuadClass = 1 or QuadClass = 2) and IsRootEvent = 1`,
+			func(ev *Event) bool { return (a(ev) || b(ev)) && c(ev) }},
+		{`not QuadClass = 1 and IsRootEvent = 1`,
+			func(ev *Event) bool { return !a(ev) && c(ev) }},
+		{`not (QuadClass = 1 or IsRootEvent = 1)`,
+			func(ev *Event) bool { return !(a(ev) || c(ev)) }},
+		{`not not QuadClass = 1`, a},
+		{`QuadClass = 1 OR QuadClass = 2 AND NOT IsRootEvent = 1`,
+			func(ev *Event) bool { return a(ev) || (b(ev) && !c(ev)) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := ParseFilter(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, ev := range events {
+				if got, want := f(ev), tt.want(ev); got != want {
+					t.Errorf("QuadClass=%d IsRootEvent=%d: expected %v, actual %v", ev.QuadClass, ev.IsRootEvent, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestParseFilterEval(t *testing.T) {
+	full := &Event{
+		GlobalEventID:  1179475420,
+		QuadClass:      4,
+		GoldsteinScale: NullableFloat64{Float64: -10, Valid: true},
+		AvgTone:        -5.25,
+		Actor1:         ActorData{CountryCode: "RUS", Type1Code: "MIL"},
+		ActionGeo: GeoData{
+			Type:        WorldCity,
+			CountryCode: "UP",
+			Lat:         NullableFloat64{Float64: 50.45, Valid: true},
+			Long:        NullableFloat64{Float64: 30.5233, Valid: true},
+		},
+		SourceURL: "https://example.com/war",
+		GKGArticle: &Article{
+			SourceCollectionIdentifier: WebMention,
+			Extras:                     ArticleExtras{PageTitle: "Shelling reported near Kyiv"},
+		},
+	}
+	empty := &Event{}
+
+	tests := []struct {
+		expr  string
+		full  bool
+		empty bool
+	}{
+		{`GlobalEventID = 1179475420`, true, false},
+		{`QuadClass >= 3 and QuadClass <= 4`, true, false},
+		{`QuadClass != 4`, false, true},
+		{`AvgTone < -5`, true, false},
+		{`AvgTone > -5.5e0`, true, true},
+		{`Actor1.CountryCode = "RUS" and Actor1.Type1Code in ("MIL", "COP")`, true, false},
+		{`Actor1.CountryCode not in ("USA", "RUS")`, false, true},
+		{`SourceURL contains "war"`, true, false},
+
+		// Null values never match a comparison.
+		{`GoldsteinScale <= -10`, true, false},
+		{`GoldsteinScale > -100`, true, false},
+		{`not GoldsteinScale > -100`, false, true},
+		{`GoldsteinScale in (-10, 10)`, true, false},
+		{`GoldsteinScale not in (1, 2)`, true, false},
+		{`ActionGeo.Lat > 50 and ActionGeo.Long < 31`, true, false},
+		{`ActionGeo.Lat != 0`, true, false},
+
+		// Nil pointers along the path never match either.
+		{`GKGArticle.Extras.PageTitle contains "Kyiv"`, true, false},
+		{`GKGArticle.Extras.PageTitle = ""`, false, false},
+		{`GKGArticle.Extras.PageTitle != "x"`, true, false},
+
+		// Enumerations are compared by name or by code.
+		{`ActionGeo.Type = "WORLDCITY"`, true, false},
+		{`ActionGeo.Type = "worldcity"`, true, false},
+		{`ActionGeo.Type = 4`, true, false},
+		{`ActionGeo.Type in ("COUNTRY", "WORLDCITY")`, true, false},
+		{`ActionGeo.Type != "COUNTRY"`, true, true},
+		{`ActionGeo.Type = 0`, false, true},
+		{`GKGArticle.SourceCollectionIdentifier = "WEB"`, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := ParseFilter(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := f(full); got != tt.full {
+				t.Errorf("full event: expected %v, actual %v", tt.full, got)
+			}
+			if got := f(empty); got != tt.empty {
+				t.Errorf("empty event: expected %v, actual %v", tt.empty, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdeltparquet
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/nlpodyssey/gdelt"
+	"github.com/parquet-go/parquet-go"
+)
+
+// partitionFileSeq distinguishes the partitioned writers created at the
+// same time.
+var partitionFileSeq atomic.Uint64
+
+// newPartitionFileName returns the name of the Parquet files written by a
+// partitioned writer, as "part-<unix-nanos>-<n>.parquet", so that several
+// runs can add files to the same partitions.
+func newPartitionFileName() string {
+	return fmt.Sprintf("part-%d-%d.parquet", time.Now().UnixNano(), partitionFileSeq.Add(1))
+}
+
+// NullPartition is the partition value of rows lacking the partitioning
+// column, following the Hive convention.
+const NullPartition = "__HIVE_DEFAULT_PARTITION__"
+
+// EventPartition returns the Hive-style "key=value" partition directory of
+// an event.
+type EventPartition func(e *gdelt.Event) string
+
+// ByDateAdded partitions events by the UTC day they were added to GDELT,
+// as "date=YYYY-MM-DD".
+func ByDateAdded(e *gdelt.Event) string {
+	t, err := e.DateAddedTime()
+	return datePartition(t, err)
+}
+
+// ByActionCountry partitions events by ActionGeo.CountryCode, as
+// "country=XX".
+func ByActionCountry(e *gdelt.Event) string {
+	c := e.ActionGeo.CountryCode
+	if len(c) == 0 {
+		c = NullPartition
+	}
+	return "country=" + c
+}
+
+// ArticlePartition returns the Hive-style "key=value" partition directory
+// of a GKG article.
+type ArticlePartition func(a *gdelt.Article) string
+
+// ArticlesByDate partitions GKG articles by the UTC day of their batch, as
+// "date=YYYY-MM-DD".
+func ArticlesByDate(a *gdelt.Article) string {
+	t, err := a.DateTime()
+	return datePartition(t, err)
+}
+
+func datePartition(t time.Time, err error) string {
+	if err != nil {
+		return "date=" + NullPartition
+	}
+	return "date=" + t.Format(time.DateOnly)
+}
+
+// PartitionedEventWriter writes events to a directory tree of Parquet
+// files, one per partition, as read by DuckDB with hive_partitioning and by
+// Spark. Each writer adds a new file to the partitions it writes, leaving
+// the existing ones untouched.
+type PartitionedEventWriter struct {
+	partition EventPartition
+	files     partitionFiles[EventRow]
+}
+
+// NewPartitionedEventWriter returns a new PartitionedEventWriter writing
+// below dir.
+func NewPartitionedEventWriter(dir string, partition EventPartition, opts Options) *PartitionedEventWriter {
+	return &PartitionedEventWriter{
+		partition: partition,
+		files:     newPartitionFiles[EventRow](dir, opts),
+	}
+}
+
+// Write writes a single event to its partition.
+func (w *PartitionedEventWriter) Write(e *gdelt.Event) error {
+	return w.files.write(w.partition(e), NewEventRow(e))
+}
+
+// WriteAll writes multiple events.
+func (w *PartitionedEventWriter) WriteAll(events []*gdelt.Event) error {
+	for _, e := range events {
+		if err := w.Write(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close completes and closes every partition file.
+func (w *PartitionedEventWriter) Close() error {
+	return w.files.close()
+}
+
+// PartitionedArticleWriter writes GKG articles to a directory tree of
+// Parquet files, one per partition. Each writer adds a new file to the
+// partitions it writes, leaving the existing ones untouched.
+type PartitionedArticleWriter struct {
+	partition ArticlePartition
+	files     partitionFiles[ArticleRow]
+}
+
+// NewPartitionedArticleWriter returns a new PartitionedArticleWriter
+// writing below dir.
+func NewPartitionedArticleWriter(dir string, partition ArticlePartition, opts Options) *PartitionedArticleWriter {
+	return &PartitionedArticleWriter{
+		partition: partition,
+		files:     newPartitionFiles[ArticleRow](dir, opts),
+	}
+}
+
+// Write writes a single article to its partition.
+func (w *PartitionedArticleWriter) Write(a *gdelt.Article) error {
+	return w.files.write(w.partition(a), NewArticleRow(a))
+}
+
+// WriteAll writes multiple articles.
+func (w *PartitionedArticleWriter) WriteAll(articles []*gdelt.Article) error {
+	for _, a := range articles {
+		if err := w.Write(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close completes and closes every partition file.
+func (w *PartitionedArticleWriter) Close() error {
+	return w.files.close()
+}
+
+// partitionFiles lazily creates a Parquet file per partition.
+type partitionFiles[T any] struct {
+	dir   string
+	name  string
+	opts  Options
+	files map[string]*partitionFile[T]
+}
+
+type partitionFile[T any] struct {
+	f *os.File
+	w *parquet.GenericWriter[T]
+}
+
+func newPartitionFiles[T any](dir string, opts Options) partitionFiles[T] {
+	return partitionFiles[T]{
+		dir:   dir,
+		name:  newPartitionFileName(),
+		opts:  opts,
+		files: make(map[string]*partitionFile[T]),
+	}
+}
+
+func (p *partitionFiles[T]) write(partition string, row T) error {
+	pf, ok := p.files[partition]
+	if !ok {
+		var err error
+		if pf, err = p.create(partition); err != nil {
+			return err
+		}
+		p.files[partition] = pf
+	}
+	_, err := pf.w.Write([]T{row})
+	return err
+}
+
+func (p *partitionFiles[T]) create(partition string) (*partitionFile[T], error) {
+	dir := filepath.Join(p.dir, filepath.FromSlash(partition))
+	if !filepath.IsLocal(partition) {
+		return nil, fmt.Errorf("invalid partition %q", partition)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create partition directory: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(dir, p.name), os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o666)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create partition file: %w", err)
+	}
+	return &partitionFile[T]{
+		f: f,
+		w: parquet.NewGenericWriter[T](f, p.opts.writerOptions()...),
+	}, nil
+}
+
+func (p *partitionFiles[T]) close() error {
+	partitions := make([]string, 0, len(p.files))
+	for partition := range p.files {
+		partitions = append(partitions, partition)
+	}
+	sort.Strings(partitions)
+
+	var errs []error
+	for _, partition := range partitions {
+		pf := p.files[partition]
+		if err := pf.w.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to write partition %q: %w", partition, err))
+		}
+		if err := pf.f.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close partition %q: %w", partition, err))
+		}
+		delete(p.files, partition)
+	}
+	return errors.Join(errs...)
+}
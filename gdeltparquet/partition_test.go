@@ -0,0 +1,188 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdeltparquet
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nlpodyssey/gdelt"
+	"github.com/parquet-go/parquet-go"
+)
+
+// readPartitions reads the rows of the Parquet files below dir, by
+// partition directory, checking that each file has at least minRowGroups
+// row groups.
+func readPartitions[T any](t *testing.T, dir string, minRowGroups int) (map[string][]T, []string) {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join(dir, "*", "*.parquet"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := make(map[string][]T)
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		info, err := f.Stat()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pf, err := parquet.OpenFile(f, info.Size())
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		if n := len(pf.RowGroups()); n < minRowGroups {
+			t.Errorf("%s has %d row groups, expected at least %d", path, n, minRowGroups)
+		}
+		r, err := parquet.Read[T](f, info.Size())
+		_ = f.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		partition := filepath.Base(filepath.Dir(path))
+		rows[partition] = append(rows[partition], r...)
+	}
+	return rows, paths
+}
+
+func testEvents(firstID uint64) []*gdelt.Event {
+	var events []*gdelt.Event
+	for i, country := range []string{"US", "US", "US", "FR", "FR", "FR", ""} {
+		e := &gdelt.Event{
+			GlobalEventID: firstID + uint64(i),
+			DateAdded:     20240614003000,
+			ActionGeo:     gdelt.GeoData{CountryCode: country},
+		}
+		if i%2 == 0 {
+			e.GoldsteinScale = gdelt.NullableFloat64{Float64: -2.5, Valid: true}
+			e.ActionGeo.Lat = gdelt.NullableFloat64{Float64: 48.8667, Valid: true}
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestPartitionedEventWriter(t *testing.T) {
+	dir := t.TempDir()
+	opts := Options{RowGroupSize: 2}
+	for _, firstID := range []uint64{100, 200} {
+		w := NewPartitionedEventWriter(dir, ByActionCountry, opts)
+		if err := w.WriteAll(testEvents(firstID)); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rows, paths := readPartitions[EventRow](t, dir, 1)
+	if len(paths) != 6 {
+		t.Fatalf("expected 6 files, actual %d: %v", len(paths), paths)
+	}
+	names := make(map[string]bool)
+	for _, path := range paths {
+		names[filepath.Base(path)] = true
+	}
+	if len(names) != 2 {
+		t.Errorf("expected 2 distinct file names, one per writer, actual %v", names)
+	}
+
+	want := map[string]int{"country=US": 6, "country=FR": 6, "country=" + NullPartition: 2}
+	if len(rows) != len(want) {
+		t.Errorf("expected partitions %v, actual %d", want, len(rows))
+	}
+	for partition, n := range want {
+		if got := len(rows[partition]); got != n {
+			t.Errorf("%s: expected %d rows, actual %d", partition, n, got)
+		}
+	}
+
+	for _, r := range rows["country=US"] {
+		isNull := (r.GlobalEventID%100)%2 == 1
+		if isNull {
+			if r.GoldsteinScale != nil || r.ActionGeoLat != nil {
+				t.Errorf("event %d: expected null GoldsteinScale and ActionGeo_Lat, actual %v, %v", r.GlobalEventID, r.GoldsteinScale, r.ActionGeoLat)
+			}
+			continue
+		}
+		if r.GoldsteinScale == nil || *r.GoldsteinScale != -2.5 {
+			t.Errorf("event %d: expected GoldsteinScale -2.5, actual %v", r.GlobalEventID, r.GoldsteinScale)
+		}
+		if r.DateAdded == nil || !r.DateAdded.Equal(time.Date(2024, 6, 14, 0, 30, 0, 0, time.UTC)) {
+			t.Errorf("event %d: unexpected DATEADDED %v", r.GlobalEventID, r.DateAdded)
+		}
+	}
+}
+
+func TestPartitionedEventWriterMultipleRowGroups(t *testing.T) {
+	dir := t.TempDir()
+	w := NewPartitionedEventWriter(dir, ByDateAdded, Options{RowGroupSize: 2})
+	if err := w.WriteAll(testEvents(1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	rows, _ := readPartitions[EventRow](t, dir, 4)
+	if got := len(rows["date=2024-06-14"]); got != 7 {
+		t.Errorf("expected 7 rows, actual %d", got)
+	}
+}
+
+func TestPartitionedArticleWriter(t *testing.T) {
+	articles := []*gdelt.Article{
+		{ID: "20240614003000-0", Date: 20240614003000, Themes: []string{"PROTEST"}},
+		{ID: "20240614003000-1", Date: 20240614003000, GCAM: map[string]float64{"wc": 120}},
+		{ID: "20240614003000-2", Date: 20240614003000},
+		{ID: "20240615000000-0", Date: 20240615000000},
+		{ID: "20240615000000-1", Date: 20240615000000},
+		{ID: "20240615000000-2", Date: 20240615000000},
+	}
+	dir := t.TempDir()
+	for i := 0; i < 2; i++ {
+		w := NewPartitionedArticleWriter(dir, ArticlesByDate, Options{RowGroupSize: 1})
+		if err := w.WriteAll(articles); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rows, paths := readPartitions[ArticleRow](t, dir, 3)
+	if len(paths) != 4 {
+		t.Fatalf("expected 4 files, actual %d: %v", len(paths), paths)
+	}
+	for _, partition := range []string{"date=2024-06-14", "date=2024-06-15"} {
+		if got := len(rows[partition]); got != 6 {
+			t.Errorf("%s: expected 6 rows, actual %d", partition, got)
+		}
+	}
+
+	byID := make(map[string]ArticleRow)
+	for _, r := range rows["date=2024-06-14"] {
+		byID[r.GKGRecordID] = r
+	}
+	if th := byID["20240614003000-0"].Themes; len(th) != 1 || th[0].Name != "PROTEST" {
+		t.Errorf("expected theme PROTEST, actual %+v", th)
+	}
+	if wc := byID["20240614003000-1"].GCAM["wc"]; wc != 120 {
+		t.Errorf("expected GCAM wc 120, actual %v", wc)
+	}
+}
@@ -0,0 +1,400 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdeltparquet
+
+import (
+	"time"
+
+	"github.com/nlpodyssey/gdelt"
+)
+
+// EventRow is the Parquet schema of events.
+//
+// Columns are named after the GDELT 2.0 export table, as in the GDELT
+// BigQuery datasets. Actors and locations are flattened into prefixed
+// columns, null GDELT values are null columns, and DATEADDED is a UTC
+// timestamp. The GKG article of an event can be joined on
+// SOURCEURL = DocumentIdentifier.
+type EventRow struct {
+	GlobalEventID int64   `parquet:"GlobalEventID"`
+	Day           int64   `parquet:"Day"`
+	MonthYear     int64   `parquet:"MonthYear"`
+	Year          int64   `parquet:"Year"`
+	FractionDate  float64 `parquet:"FractionDate"`
+
+	Actor1Code           string `parquet:"Actor1Code"`
+	Actor1Name           string `parquet:"Actor1Name"`
+	Actor1CountryCode    string `parquet:"Actor1CountryCode"`
+	Actor1KnownGroupCode string `parquet:"Actor1KnownGroupCode"`
+	Actor1EthnicCode     string `parquet:"Actor1EthnicCode"`
+	Actor1Religion1Code  string `parquet:"Actor1Religion1Code"`
+	Actor1Religion2Code  string `parquet:"Actor1Religion2Code"`
+	Actor1Type1Code      string `parquet:"Actor1Type1Code"`
+	Actor1Type2Code      string `parquet:"Actor1Type2Code"`
+	Actor1Type3Code      string `parquet:"Actor1Type3Code"`
+
+	Actor2Code           string `parquet:"Actor2Code"`
+	Actor2Name           string `parquet:"Actor2Name"`
+	Actor2CountryCode    string `parquet:"Actor2CountryCode"`
+	Actor2KnownGroupCode string `parquet:"Actor2KnownGroupCode"`
+	Actor2EthnicCode     string `parquet:"Actor2EthnicCode"`
+	Actor2Religion1Code  string `parquet:"Actor2Religion1Code"`
+	Actor2Religion2Code  string `parquet:"Actor2Religion2Code"`
+	Actor2Type1Code      string `parquet:"Actor2Type1Code"`
+	Actor2Type2Code      string `parquet:"Actor2Type2Code"`
+	Actor2Type3Code      string `parquet:"Actor2Type3Code"`
+
+	IsRootEvent    int64    `parquet:"IsRootEvent"`
+	EventCode      string   `parquet:"EventCode"`
+	EventBaseCode  string   `parquet:"EventBaseCode"`
+	EventRootCode  string   `parquet:"EventRootCode"`
+	QuadClass      int64    `parquet:"QuadClass"`
+	GoldsteinScale *float64 `parquet:"GoldsteinScale,optional"`
+	NumMentions    int64    `parquet:"NumMentions"`
+	NumSources     int64    `parquet:"NumSources"`
+	NumArticles    int64    `parquet:"NumArticles"`
+	AvgTone        float64  `parquet:"AvgTone"`
+
+	Actor1GeoType        int64    `parquet:"Actor1Geo_Type"`
+	Actor1GeoFullName    string   `parquet:"Actor1Geo_FullName"`
+	Actor1GeoCountryCode string   `parquet:"Actor1Geo_CountryCode"`
+	Actor1GeoADM1Code    string   `parquet:"Actor1Geo_ADM1Code"`
+	Actor1GeoADM2Code    string   `parquet:"Actor1Geo_ADM2Code"`
+	Actor1GeoLat         *float64 `parquet:"Actor1Geo_Lat,optional"`
+	Actor1GeoLong        *float64 `parquet:"Actor1Geo_Long,optional"`
+	Actor1GeoFeatureID   string   `parquet:"Actor1Geo_FeatureID"`
+
+	Actor2GeoType        int64    `parquet:"Actor2Geo_Type"`
+	Actor2GeoFullName    string   `parquet:"Actor2Geo_FullName"`
+	Actor2GeoCountryCode string   `parquet:"Actor2Geo_CountryCode"`
+	Actor2GeoADM1Code    string   `parquet:"Actor2Geo_ADM1Code"`
+	Actor2GeoADM2Code    string   `parquet:"Actor2Geo_ADM2Code"`
+	Actor2GeoLat         *float64 `parquet:"Actor2Geo_Lat,optional"`
+	Actor2GeoLong        *float64 `parquet:"Actor2Geo_Long,optional"`
+	Actor2GeoFeatureID   string   `parquet:"Actor2Geo_FeatureID"`
+
+	ActionGeoType        int64    `parquet:"ActionGeo_Type"`
+	ActionGeoFullName    string   `parquet:"ActionGeo_FullName"`
+	ActionGeoCountryCode string   `parquet:"ActionGeo_CountryCode"`
+	ActionGeoADM1Code    string   `parquet:"ActionGeo_ADM1Code"`
+	ActionGeoADM2Code    string   `parquet:"ActionGeo_ADM2Code"`
+	ActionGeoLat         *float64 `parquet:"ActionGeo_Lat,optional"`
+	ActionGeoLong        *float64 `parquet:"ActionGeo_Long,optional"`
+	ActionGeoFeatureID   string   `parquet:"ActionGeo_FeatureID"`
+
+	DateAdded *time.Time `parquet:"DATEADDED,optional,timestamp(millisecond)"`
+	SourceURL string     `parquet:"SOURCEURL"`
+}
+
+// NewEventRow converts an event to an EventRow.
+func NewEventRow(e *gdelt.Event) EventRow {
+	a1, a2 := e.Actor1, e.Actor2
+	g1, g2, ga := e.Actor1Geo, e.Actor2Geo, e.ActionGeo
+	return EventRow{
+		GlobalEventID: int64(e.GlobalEventID),
+		Day:           int64(e.Day),
+		MonthYear:     int64(e.MonthYear),
+		Year:          int64(e.Year),
+		FractionDate:  e.FractionDate,
+
+		Actor1Code:           a1.Code,
+		Actor1Name:           a1.Name,
+		Actor1CountryCode:    a1.CountryCode,
+		Actor1KnownGroupCode: a1.KnownGroupCode,
+		Actor1EthnicCode:     a1.EthnicCode,
+		Actor1Religion1Code:  a1.Religion1Code,
+		Actor1Religion2Code:  a1.Religion2Code,
+		Actor1Type1Code:      a1.Type1Code,
+		Actor1Type2Code:      a1.Type2Code,
+		Actor1Type3Code:      a1.Type3Code,
+
+		Actor2Code:           a2.Code,
+		Actor2Name:           a2.Name,
+		Actor2CountryCode:    a2.CountryCode,
+		Actor2KnownGroupCode: a2.KnownGroupCode,
+		Actor2EthnicCode:     a2.EthnicCode,
+		Actor2Religion1Code:  a2.Religion1Code,
+		Actor2Religion2Code:  a2.Religion2Code,
+		Actor2Type1Code:      a2.Type1Code,
+		Actor2Type2Code:      a2.Type2Code,
+		Actor2Type3Code:      a2.Type3Code,
+
+		IsRootEvent:    int64(e.IsRootEvent),
+		EventCode:      e.EventCode,
+		EventBaseCode:  e.EventBaseCode,
+		EventRootCode:  e.EventRootCode,
+		QuadClass:      int64(e.QuadClass),
+		GoldsteinScale: nullable(e.GoldsteinScale),
+		NumMentions:    int64(e.NumMentions),
+		NumSources:     int64(e.NumSources),
+		NumArticles:    int64(e.NumArticles),
+		AvgTone:        e.AvgTone,
+
+		Actor1GeoType:        int64(g1.Type),
+		Actor1GeoFullName:    g1.Fullname,
+		Actor1GeoCountryCode: g1.CountryCode,
+		Actor1GeoADM1Code:    g1.ADM1Code,
+		Actor1GeoADM2Code:    g1.ADM2Code,
+		Actor1GeoLat:         nullable(g1.Lat),
+		Actor1GeoLong:        nullable(g1.Long),
+		Actor1GeoFeatureID:   g1.FeatureID,
+
+		Actor2GeoType:        int64(g2.Type),
+		Actor2GeoFullName:    g2.Fullname,
+		Actor2GeoCountryCode: g2.CountryCode,
+		Actor2GeoADM1Code:    g2.ADM1Code,
+		Actor2GeoADM2Code:    g2.ADM2Code,
+		Actor2GeoLat:         nullable(g2.Lat),
+		Actor2GeoLong:        nullable(g2.Long),
+		Actor2GeoFeatureID:   g2.FeatureID,
+
+		ActionGeoType:        int64(ga.Type),
+		ActionGeoFullName:    ga.Fullname,
+		ActionGeoCountryCode: ga.CountryCode,
+		ActionGeoADM1Code:    ga.ADM1Code,
+		ActionGeoADM2Code:    ga.ADM2Code,
+		ActionGeoLat:         nullable(ga.Lat),
+		ActionGeoLong:        nullable(ga.Long),
+		ActionGeoFeatureID:   ga.FeatureID,
+
+		DateAdded: timestamp(e.DateAddedTime()),
+		SourceURL: e.SourceURL,
+	}
+}
+
+// ArticleRow is the Parquet schema of GKG articles.
+//
+// Columns are named after the GKG 2.1 table. Delimited GKG fields are
+// lists of structs, using the enhanced (character offset) variants when
+// available, and Tone and TranslationInfo are flattened. DATE is a UTC
+// timestamp.
+type ArticleRow struct {
+	GKGRecordID                string     `parquet:"GKGRECORDID"`
+	Date                       *time.Time `parquet:"DATE,optional,timestamp(millisecond)"`
+	SourceCollectionIdentifier string     `parquet:"SourceCollectionIdentifier"`
+	SourceCommonName           string     `parquet:"SourceCommonName"`
+	DocumentIdentifier         string     `parquet:"DocumentIdentifier"`
+
+	Counts        []CountRow       `parquet:"Counts,list"`
+	Themes        []NamedOffsetRow `parquet:"Themes,list"`
+	Locations     []LocationRow    `parquet:"Locations,list"`
+	Persons       []NamedOffsetRow `parquet:"Persons,list"`
+	Organizations []NamedOffsetRow `parquet:"Organizations,list"`
+
+	Tone                      float64 `parquet:"Tone"`
+	PositiveScore             float64 `parquet:"PositiveScore"`
+	NegativeScore             float64 `parquet:"NegativeScore"`
+	Polarity                  float64 `parquet:"Polarity"`
+	ActivityReferenceDensity  float64 `parquet:"ActivityReferenceDensity"`
+	SelfGroupReferenceDensity float64 `parquet:"SelfGroupReferenceDensity"`
+	WordCount                 int64   `parquet:"WordCount"`
+
+	Dates []DateRow          `parquet:"Dates,list"`
+	GCAM  map[string]float64 `parquet:"GCAM"`
+
+	SharingImage      string   `parquet:"SharingImage"`
+	RelatedImages     []string `parquet:"RelatedImages,list"`
+	SocialImageEmbeds []string `parquet:"SocialImageEmbeds,list"`
+	SocialVideoEmbeds []string `parquet:"SocialVideoEmbeds,list"`
+
+	Quotations []QuotationRow   `parquet:"Quotations,list"`
+	AllNames   []NamedOffsetRow `parquet:"AllNames,list"`
+	Amounts    []AmountRow      `parquet:"Amounts,list"`
+
+	SourceLanguage    string `parquet:"SourceLanguage"`
+	TranslationEngine string `parquet:"TranslationEngine"`
+	PageTitle         string `parquet:"PageTitle"`
+}
+
+// CountRow is an element of ArticleRow.Counts.
+type CountRow struct {
+	CountType  string      `parquet:"CountType"`
+	Number     int64       `parquet:"Number"`
+	ObjectType string      `parquet:"ObjectType"`
+	Location   LocationRow `parquet:"Location"`
+}
+
+// NamedOffsetRow is an element of the ArticleRow name lists.
+type NamedOffsetRow struct {
+	Name       string `parquet:"Name"`
+	CharOffset int64  `parquet:"CharOffset"`
+}
+
+// LocationRow is an element of ArticleRow.Locations. CharOffset is -1 when
+// unknown.
+type LocationRow struct {
+	Type        int64    `parquet:"Type"`
+	FullName    string   `parquet:"FullName"`
+	CountryCode string   `parquet:"CountryCode"`
+	ADM1Code    string   `parquet:"ADM1Code"`
+	ADM2Code    string   `parquet:"ADM2Code"`
+	Lat         *float64 `parquet:"Lat,optional"`
+	Long        *float64 `parquet:"Long,optional"`
+	FeatureID   string   `parquet:"FeatureID"`
+	CharOffset  int64    `parquet:"CharOffset"`
+}
+
+// DateRow is an element of ArticleRow.Dates.
+type DateRow struct {
+	Resolution int64 `parquet:"Resolution"`
+	Month      int64 `parquet:"Month"`
+	Day        int64 `parquet:"Day"`
+	Year       int64 `parquet:"Year"`
+	CharOffset int64 `parquet:"CharOffset"`
+}
+
+// QuotationRow is an element of ArticleRow.Quotations.
+type QuotationRow struct {
+	CharOffset int64  `parquet:"CharOffset"`
+	Length     int64  `parquet:"Length"`
+	Verb       string `parquet:"Verb"`
+	Quote      string `parquet:"Quote"`
+}
+
+// AmountRow is an element of ArticleRow.Amounts.
+type AmountRow struct {
+	Amount     float64 `parquet:"Amount"`
+	Object     string  `parquet:"Object"`
+	CharOffset int64   `parquet:"CharOffset"`
+}
+
+// NewArticleRow converts a GKG article to an ArticleRow.
+func NewArticleRow(a *gdelt.Article) ArticleRow {
+	r := ArticleRow{
+		GKGRecordID:                a.ID,
+		Date:                       timestamp(a.DateTime()),
+		SourceCollectionIdentifier: a.SourceCollectionIdentifier.String(),
+		SourceCommonName:           a.SourceCommonName,
+		DocumentIdentifier:         a.DocumentIdentifier,
+
+		Themes:        namedOffsetRows(a.EnhancedThemes, a.Themes),
+		Persons:       namedOffsetRows(a.EnhancedPersons, a.Persons),
+		Organizations: namedOffsetRows(a.EnhancedOrganizations, a.Organizations),
+
+		Tone:                      a.Tone.Tone,
+		PositiveScore:             a.Tone.PositiveScore,
+		NegativeScore:             a.Tone.NegativeScore,
+		Polarity:                  a.Tone.Polarity,
+		ActivityReferenceDensity:  a.Tone.ActivityReferenceDensity,
+		SelfGroupReferenceDensity: a.Tone.SelfGroupReferenceDensity,
+		WordCount:                 int64(a.Tone.WordCount),
+
+		GCAM: a.GCAM,
+
+		SharingImage:      a.SharingImage,
+		RelatedImages:     a.RelatedImages,
+		SocialImageEmbeds: a.SocialImageEmbeds,
+		SocialVideoEmbeds: a.SocialVideoEmbeds,
+
+		AllNames: namedOffsetRows(a.AllNames, nil),
+
+		SourceLanguage:    a.TranslationInfo.SourceLanguage,
+		TranslationEngine: a.TranslationInfo.Engine,
+		PageTitle:         a.Extras.PageTitle,
+	}
+
+	counts := a.EnhancedCounts
+	if len(counts) == 0 {
+		counts = a.Counts
+	}
+	for _, c := range counts {
+		loc := locationRow(c.Location, c.CharOffset)
+		r.Counts = append(r.Counts, CountRow{
+			CountType:  c.CountType,
+			Number:     c.Number,
+			ObjectType: c.ObjectType,
+			Location:   loc,
+		})
+	}
+
+	locations := a.EnhancedLocations
+	if len(locations) == 0 {
+		locations = a.Locations
+	}
+	for _, l := range locations {
+		r.Locations = append(r.Locations, locationRow(l.GeoData, l.CharOffset))
+	}
+
+	for _, d := range a.Dates {
+		r.Dates = append(r.Dates, DateRow{
+			Resolution: int64(d.Resolution),
+			Month:      int64(d.Month),
+			Day:        int64(d.Day),
+			Year:       int64(d.Year),
+			CharOffset: int64(d.CharOffset),
+		})
+	}
+	for _, q := range a.Quotations {
+		r.Quotations = append(r.Quotations, QuotationRow{
+			CharOffset: int64(q.CharOffset),
+			Length:     int64(q.Length),
+			Verb:       q.Verb,
+			Quote:      q.Quote,
+		})
+	}
+	for _, am := range a.Amounts {
+		r.Amounts = append(r.Amounts, AmountRow{
+			Amount:     am.Amount,
+			Object:     am.Object,
+			CharOffset: int64(am.CharOffset),
+		})
+	}
+	return r
+}
+
+// namedOffsetRows converts the enhanced variant of a GKG name list, or the
+// plain one, without offsets, if the former is empty.
+func namedOffsetRows(enhanced []gdelt.NamedOffset, plain []string) []NamedOffsetRow {
+	var rows []NamedOffsetRow
+	for _, n := range enhanced {
+		rows = append(rows, NamedOffsetRow{Name: n.Name, CharOffset: int64(n.CharOffset)})
+	}
+	if len(rows) > 0 {
+		return rows
+	}
+	for _, name := range plain {
+		rows = append(rows, NamedOffsetRow{Name: name, CharOffset: -1})
+	}
+	return rows
+}
+
+func locationRow(g gdelt.GeoData, charOffset int) LocationRow {
+	return LocationRow{
+		Type:        int64(g.Type),
+		FullName:    g.Fullname,
+		CountryCode: g.CountryCode,
+		ADM1Code:    g.ADM1Code,
+		ADM2Code:    g.ADM2Code,
+		Lat:         nullable(g.Lat),
+		Long:        nullable(g.Long),
+		FeatureID:   g.FeatureID,
+		CharOffset:  int64(charOffset),
+	}
+}
+
+func nullable(n gdelt.NullableFloat64) *float64 {
+	if !n.Valid {
+		return nil
+	}
+	return &n.Float64
+}
+
+// timestamp returns nil for invalid "YYYYMMDDHHMMSS" times.
+func timestamp(t time.Time, err error) *time.Time {
+	if err != nil || t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,124 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gdeltparquet writes GDELT events and GKG articles to Apache
+// Parquet files, for querying with tools such as DuckDB and Spark.
+//
+// It is a separate module, so that the gdelt package does not depend on
+// the Parquet implementation.
+package gdeltparquet
+
+import (
+	"io"
+
+	"github.com/nlpodyssey/gdelt"
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+)
+
+// DefaultRowGroupSize is the default maximum number of rows per row group.
+const DefaultRowGroupSize = 128 * 1024
+
+// Options configures the Parquet writers.
+type Options struct {
+	// RowGroupSize is the maximum number of rows per row group. Zero means
+	// DefaultRowGroupSize.
+	RowGroupSize int64
+	// Compression is the compression codec of the column chunks. Nil means
+	// Zstandard.
+	Compression compress.Codec
+}
+
+func (o Options) writerOptions() []parquet.WriterOption {
+	rowGroupSize := o.RowGroupSize
+	if rowGroupSize <= 0 {
+		rowGroupSize = DefaultRowGroupSize
+	}
+	var codec compress.Codec = &parquet.Zstd
+	if o.Compression != nil {
+		codec = o.Compression
+	}
+	return []parquet.WriterOption{
+		parquet.MaxRowsPerRowGroup(rowGroupSize),
+		parquet.Compression(codec),
+	}
+}
+
+// EventWriter writes events to a Parquet file with the EventRow schema.
+type EventWriter struct {
+	w *parquet.GenericWriter[EventRow]
+}
+
+// NewEventWriter returns a new EventWriter writing to w.
+func NewEventWriter(w io.Writer, opts Options) *EventWriter {
+	return &EventWriter{w: parquet.NewGenericWriter[EventRow](w, opts.writerOptions()...)}
+}
+
+// Write writes a single event. Rows are buffered up to the row group size,
+// so Close must eventually be called to ensure that the event is written.
+func (w *EventWriter) Write(e *gdelt.Event) error {
+	_, err := w.w.Write([]EventRow{NewEventRow(e)})
+	return err
+}
+
+// WriteAll writes multiple events.
+func (w *EventWriter) WriteAll(events []*gdelt.Event) error {
+	rows := make([]EventRow, len(events))
+	for i, e := range events {
+		rows[i] = NewEventRow(e)
+	}
+	_, err := w.w.Write(rows)
+	return err
+}
+
+// Close flushes the buffered rows and writes the Parquet footer. It does
+// not close the underlying io.Writer.
+func (w *EventWriter) Close() error {
+	return w.w.Close()
+}
+
+// ArticleWriter writes GKG articles to a Parquet file with the ArticleRow
+// schema.
+type ArticleWriter struct {
+	w *parquet.GenericWriter[ArticleRow]
+}
+
+// NewArticleWriter returns a new ArticleWriter writing to w.
+func NewArticleWriter(w io.Writer, opts Options) *ArticleWriter {
+	return &ArticleWriter{w: parquet.NewGenericWriter[ArticleRow](w, opts.writerOptions()...)}
+}
+
+// Write writes a single article. Rows are buffered up to the row group
+// size, so Close must eventually be called to ensure that the article is
+// written.
+func (w *ArticleWriter) Write(a *gdelt.Article) error {
+	_, err := w.w.Write([]ArticleRow{NewArticleRow(a)})
+	return err
+}
+
+// WriteAll writes multiple articles.
+func (w *ArticleWriter) WriteAll(articles []*gdelt.Article) error {
+	rows := make([]ArticleRow, len(articles))
+	for i, a := range articles {
+		rows[i] = NewArticleRow(a)
+	}
+	_, err := w.w.Write(rows)
+	return err
+}
+
+// Close flushes the buffered rows and writes the Parquet footer. It does
+// not close the underlying io.Writer.
+func (w *ArticleWriter) Close() error {
+	return w.w.Close()
+}
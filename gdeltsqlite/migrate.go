@@ -0,0 +1,213 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdeltsqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// migrations are the SQL statements bringing the database schema from one
+// version to the next: applying migrations[i] upgrades version i to i+1.
+// The schema version is stored in the SQLite user_version pragma.
+//
+// Migrations must never be changed once released; schema changes are made
+// by appending a new migration.
+var migrations = [][]string{
+	{
+		`CREATE TABLE events (
+			global_event_id INTEGER PRIMARY KEY,
+			day INTEGER NOT NULL,
+			month_year INTEGER NOT NULL,
+			year INTEGER NOT NULL,
+			fraction_date REAL NOT NULL,
+			actor1_code TEXT NOT NULL,
+			actor1_name TEXT NOT NULL,
+			actor1_country_code TEXT NOT NULL,
+			actor1_known_group_code TEXT NOT NULL,
+			actor1_ethnic_code TEXT NOT NULL,
+			actor1_religion1_code TEXT NOT NULL,
+			actor1_religion2_code TEXT NOT NULL,
+			actor1_type1_code TEXT NOT NULL,
+			actor1_type2_code TEXT NOT NULL,
+			actor1_type3_code TEXT NOT NULL,
+			actor2_code TEXT NOT NULL,
+			actor2_name TEXT NOT NULL,
+			actor2_country_code TEXT NOT NULL,
+			actor2_known_group_code TEXT NOT NULL,
+			actor2_ethnic_code TEXT NOT NULL,
+			actor2_religion1_code TEXT NOT NULL,
+			actor2_religion2_code TEXT NOT NULL,
+			actor2_type1_code TEXT NOT NULL,
+			actor2_type2_code TEXT NOT NULL,
+			actor2_type3_code TEXT NOT NULL,
+			is_root_event INTEGER NOT NULL,
+			event_code TEXT NOT NULL,
+			event_base_code TEXT NOT NULL,
+			event_root_code TEXT NOT NULL,
+			quad_class INTEGER NOT NULL,
+			goldstein_scale REAL,
+			num_mentions INTEGER NOT NULL,
+			num_sources INTEGER NOT NULL,
+			num_articles INTEGER NOT NULL,
+			avg_tone REAL NOT NULL,
+			actor1_geo_type INTEGER NOT NULL,
+			actor1_geo_full_name TEXT NOT NULL,
+			actor1_geo_country_code TEXT NOT NULL,
+			actor1_geo_adm1_code TEXT NOT NULL,
+			actor1_geo_adm2_code TEXT NOT NULL,
+			actor1_geo_lat REAL,
+			actor1_geo_long REAL,
+			actor1_geo_feature_id TEXT NOT NULL,
+			actor2_geo_type INTEGER NOT NULL,
+			actor2_geo_full_name TEXT NOT NULL,
+			actor2_geo_country_code TEXT NOT NULL,
+			actor2_geo_adm1_code TEXT NOT NULL,
+			actor2_geo_adm2_code TEXT NOT NULL,
+			actor2_geo_lat REAL,
+			actor2_geo_long REAL,
+			actor2_geo_feature_id TEXT NOT NULL,
+			action_geo_type INTEGER NOT NULL,
+			action_geo_full_name TEXT NOT NULL,
+			action_geo_country_code TEXT NOT NULL,
+			action_geo_adm1_code TEXT NOT NULL,
+			action_geo_adm2_code TEXT NOT NULL,
+			action_geo_lat REAL,
+			action_geo_long REAL,
+			action_geo_feature_id TEXT NOT NULL,
+			date_added INTEGER NOT NULL,
+			source_url TEXT NOT NULL
+		)`,
+		`CREATE INDEX events_source_url ON events (source_url)`,
+		`CREATE INDEX events_date_added ON events (date_added)`,
+		`CREATE INDEX events_action_geo_country_code ON events (action_geo_country_code)`,
+
+		`CREATE TABLE mentions (
+			global_event_id INTEGER NOT NULL,
+			event_time_date INTEGER NOT NULL,
+			mention_time_date INTEGER NOT NULL,
+			mention_type INTEGER NOT NULL,
+			mention_source_name TEXT NOT NULL,
+			mention_identifier TEXT NOT NULL,
+			sentence_id INTEGER NOT NULL,
+			actor1_char_offset INTEGER NOT NULL,
+			actor2_char_offset INTEGER NOT NULL,
+			action_char_offset INTEGER NOT NULL,
+			in_raw_text INTEGER NOT NULL,
+			confidence INTEGER NOT NULL,
+			mention_doc_len INTEGER NOT NULL,
+			mention_doc_tone REAL NOT NULL,
+			mention_doc_translation_info TEXT NOT NULL,
+			PRIMARY KEY (global_event_id, mention_identifier, sentence_id)
+		)`,
+		`CREATE INDEX mentions_mention_identifier ON mentions (mention_identifier)`,
+
+		`CREATE TABLE articles (
+			gkg_record_id TEXT PRIMARY KEY,
+			date INTEGER NOT NULL,
+			source_collection_identifier INTEGER NOT NULL,
+			source_common_name TEXT NOT NULL,
+			document_identifier TEXT NOT NULL,
+			tone REAL NOT NULL,
+			positive_score REAL NOT NULL,
+			negative_score REAL NOT NULL,
+			polarity REAL NOT NULL,
+			activity_reference_density REAL NOT NULL,
+			self_group_reference_density REAL NOT NULL,
+			word_count INTEGER NOT NULL,
+			sharing_image TEXT NOT NULL,
+			source_language TEXT NOT NULL,
+			translation_engine TEXT NOT NULL,
+			page_title TEXT NOT NULL
+		)`,
+		`CREATE INDEX articles_document_identifier ON articles (document_identifier)`,
+
+		`CREATE TABLE article_names (
+			gkg_record_id TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			position INTEGER NOT NULL,
+			name TEXT NOT NULL,
+			char_offset INTEGER NOT NULL,
+			PRIMARY KEY (gkg_record_id, kind, position)
+		)`,
+		`CREATE INDEX article_names_kind_name ON article_names (kind, name)`,
+
+		`CREATE TABLE article_locations (
+			gkg_record_id TEXT NOT NULL,
+			position INTEGER NOT NULL,
+			type INTEGER NOT NULL,
+			full_name TEXT NOT NULL,
+			country_code TEXT NOT NULL,
+			adm1_code TEXT NOT NULL,
+			adm2_code TEXT NOT NULL,
+			lat REAL,
+			long REAL,
+			feature_id TEXT NOT NULL,
+			char_offset INTEGER NOT NULL,
+			PRIMARY KEY (gkg_record_id, position)
+		)`,
+
+		`CREATE TABLE article_gcam (
+			gkg_record_id TEXT NOT NULL,
+			dimension TEXT NOT NULL,
+			value REAL NOT NULL,
+			PRIMARY KEY (gkg_record_id, dimension)
+		)`,
+	},
+}
+
+// SchemaVersion is the database schema version created by Migrate.
+var SchemaVersion = len(migrations)
+
+// Migrate creates the database tables, or upgrades them to SchemaVersion,
+// in a single transaction. It fails if the database was created by a newer
+// version of this package.
+func Migrate(ctx context.Context, db *sql.DB) (err error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var version int
+	if err = tx.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if version > SchemaVersion {
+		return fmt.Errorf("database schema version %d is newer than the supported version %d", version, SchemaVersion)
+	}
+
+	for ; version < SchemaVersion; version++ {
+		for _, stmt := range migrations[version] {
+			if _, err = tx.ExecContext(ctx, stmt); err != nil {
+				return fmt.Errorf("failed to migrate schema to version %d: %w", version+1, err)
+			}
+		}
+	}
+	// PRAGMA statements do not accept bound parameters.
+	if _, err = tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", SchemaVersion)); err != nil {
+		return fmt.Errorf("failed to write schema version: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit schema migration: %w", err)
+	}
+	return nil
+}
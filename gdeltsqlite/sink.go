@@ -0,0 +1,356 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gdeltsqlite stores GDELT events, mentions and GKG articles in a
+// SQLite database.
+//
+// The package uses database/sql with the pure-Go modernc.org/sqlite
+// driver, which it registers as "sqlite":
+//
+//	db, err := sql.Open("sqlite", "gdelt.db")
+//
+// A database/sql pool opens several connections, and each connection to
+// ":memory:" gets its own empty database: call db.SetMaxOpenConns(1) when
+// using an in-memory database.
+//
+// Events, mentions and articles are stored in the "events", "mentions" and
+// "articles" tables. The themes, persons, organizations and other names of
+// an article are stored in "article_names", its locations in
+// "article_locations", and its GCAM values in "article_gcam". Times are
+// integers in GDELT "YYYYMMDDHHMMSS" format, and enumerations are their
+// GDELT integer codes.
+package gdeltsqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/nlpodyssey/gdelt"
+	_ "modernc.org/sqlite"
+)
+
+// Sink writes GDELT data to a SQLite database.
+//
+// Writes are upserts: rows are keyed by GlobalEventID, by GlobalEventID,
+// MentionIdentifier and SentenceID for mentions, and by GKG record ID for
+// articles, so that re-ingesting the same data replaces the existing rows.
+type Sink struct {
+	db *sql.DB
+}
+
+// NewSink returns a new Sink writing to db, after migrating the database
+// schema to SchemaVersion.
+func NewSink(ctx context.Context, db *sql.DB) (*Sink, error) {
+	if err := Migrate(ctx, db); err != nil {
+		return nil, err
+	}
+	return &Sink{db: db}, nil
+}
+
+// WriteBatch writes the events, mentions and GKG articles of a batch in a
+// single transaction.
+func (s *Sink) WriteBatch(ctx context.Context, b *gdelt.Batch) error {
+	return s.withTx(ctx, func(tx *txWriter) error {
+		for _, e := range b.Events {
+			if err := tx.writeEvent(ctx, e); err != nil {
+				return err
+			}
+		}
+		for _, m := range b.Mentions {
+			if err := tx.writeMention(ctx, m); err != nil {
+				return err
+			}
+		}
+		for _, a := range b.Articles {
+			if err := tx.writeArticle(ctx, a); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// WriteEvents writes events, along with their GKG articles and mentions,
+// in a single transaction.
+func (s *Sink) WriteEvents(ctx context.Context, events []*gdelt.Event) error {
+	return s.withTx(ctx, func(tx *txWriter) error {
+		for _, e := range events {
+			if err := tx.writeEvent(ctx, e); err != nil {
+				return err
+			}
+			for _, m := range e.Mentions {
+				if err := tx.writeMention(ctx, m); err != nil {
+					return err
+				}
+			}
+			articles := e.GKGArticles
+			if len(articles) == 0 && e.GKGArticle != nil {
+				articles = []*gdelt.Article{e.GKGArticle}
+			}
+			for _, a := range articles {
+				if err := tx.writeArticle(ctx, a); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Sink) withTx(ctx context.Context, fn func(tx *txWriter) error) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	w := &txWriter{tx: tx, stmts: make(map[string]*sql.Stmt)}
+	defer func() {
+		if e := w.close(); e != nil && err == nil {
+			err = e
+		}
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if err = fn(w); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// txWriter writes rows within a transaction, preparing each statement once.
+type txWriter struct {
+	tx    *sql.Tx
+	stmts map[string]*sql.Stmt
+}
+
+func (w *txWriter) exec(ctx context.Context, query string, args ...any) error {
+	stmt, ok := w.stmts[query]
+	if !ok {
+		var err error
+		if stmt, err = w.tx.PrepareContext(ctx, query); err != nil {
+			return fmt.Errorf("failed to prepare statement: %w", err)
+		}
+		w.stmts[query] = stmt
+	}
+	_, err := stmt.ExecContext(ctx, args...)
+	return err
+}
+
+func (w *txWriter) close() error {
+	var err error
+	for _, stmt := range w.stmts {
+		if e := stmt.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+var (
+	upsertEventSQL = upsertSQL("events", []string{"global_event_id"},
+		"global_event_id", "day", "month_year", "year", "fraction_date",
+		"actor1_code", "actor1_name", "actor1_country_code", "actor1_known_group_code", "actor1_ethnic_code",
+		"actor1_religion1_code", "actor1_religion2_code", "actor1_type1_code", "actor1_type2_code", "actor1_type3_code",
+		"actor2_code", "actor2_name", "actor2_country_code", "actor2_known_group_code", "actor2_ethnic_code",
+		"actor2_religion1_code", "actor2_religion2_code", "actor2_type1_code", "actor2_type2_code", "actor2_type3_code",
+		"is_root_event", "event_code", "event_base_code", "event_root_code", "quad_class", "goldstein_scale",
+		"num_mentions", "num_sources", "num_articles", "avg_tone",
+		"actor1_geo_type", "actor1_geo_full_name", "actor1_geo_country_code", "actor1_geo_adm1_code",
+		"actor1_geo_adm2_code", "actor1_geo_lat", "actor1_geo_long", "actor1_geo_feature_id",
+		"actor2_geo_type", "actor2_geo_full_name", "actor2_geo_country_code", "actor2_geo_adm1_code",
+		"actor2_geo_adm2_code", "actor2_geo_lat", "actor2_geo_long", "actor2_geo_feature_id",
+		"action_geo_type", "action_geo_full_name", "action_geo_country_code", "action_geo_adm1_code",
+		"action_geo_adm2_code", "action_geo_lat", "action_geo_long", "action_geo_feature_id",
+		"date_added", "source_url",
+	)
+
+	upsertMentionSQL = upsertSQL("mentions", []string{"global_event_id", "mention_identifier", "sentence_id"},
+		"global_event_id", "event_time_date", "mention_time_date", "mention_type", "mention_source_name",
+		"mention_identifier", "sentence_id", "actor1_char_offset", "actor2_char_offset", "action_char_offset",
+		"in_raw_text", "confidence", "mention_doc_len", "mention_doc_tone", "mention_doc_translation_info",
+	)
+
+	upsertArticleSQL = upsertSQL("articles", []string{"gkg_record_id"},
+		"gkg_record_id", "date", "source_collection_identifier", "source_common_name", "document_identifier",
+		"tone", "positive_score", "negative_score", "polarity", "activity_reference_density",
+		"self_group_reference_density", "word_count", "sharing_image", "source_language",
+		"translation_engine", "page_title",
+	)
+
+	insertArticleNameSQL = insertSQL("article_names",
+		"gkg_record_id", "kind", "position", "name", "char_offset")
+
+	insertArticleLocationSQL = insertSQL("article_locations",
+		"gkg_record_id", "position", "type", "full_name", "country_code", "adm1_code", "adm2_code",
+		"lat", "long", "feature_id", "char_offset")
+
+	insertArticleGCAMSQL = insertSQL("article_gcam", "gkg_record_id", "dimension", "value")
+)
+
+// articleChildTables are the tables holding the lists of an article,
+// which are replaced as a whole when the article is written again.
+var articleChildTables = []string{"article_names", "article_locations", "article_gcam"}
+
+func insertSQL(table string, columns ...string) string {
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		table, strings.Join(columns, ", "), strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "))
+}
+
+func upsertSQL(table string, key []string, columns ...string) string {
+	isKey := make(map[string]bool, len(key))
+	for _, k := range key {
+		isKey[k] = true
+	}
+	var set []string
+	for _, c := range columns {
+		if !isKey[c] {
+			set = append(set, c+" = excluded."+c)
+		}
+	}
+	return fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s",
+		insertSQL(table, columns...), strings.Join(key, ", "), strings.Join(set, ", "))
+}
+
+func (w *txWriter) writeEvent(ctx context.Context, e *gdelt.Event) error {
+	args := []any{int64(e.GlobalEventID), e.Day, e.MonthYear, e.Year, e.FractionDate}
+	args = appendActorData(args, e.Actor1)
+	args = appendActorData(args, e.Actor2)
+	args = append(args,
+		e.IsRootEvent, e.EventCode, e.EventBaseCode, e.EventRootCode, e.QuadClass,
+		nullFloat64(e.GoldsteinScale), e.NumMentions, e.NumSources, e.NumArticles, e.AvgTone,
+	)
+	args = appendGeoData(args, e.Actor1Geo)
+	args = appendGeoData(args, e.Actor2Geo)
+	args = appendGeoData(args, e.ActionGeo)
+	args = append(args, int64(e.DateAdded), e.SourceURL)
+
+	if err := w.exec(ctx, upsertEventSQL, args...); err != nil {
+		return fmt.Errorf("failed to write event %d: %w", e.GlobalEventID, err)
+	}
+	return nil
+}
+
+func (w *txWriter) writeMention(ctx context.Context, m *gdelt.Mention) error {
+	err := w.exec(ctx, upsertMentionSQL,
+		int64(m.GlobalEventID), int64(m.EventTimeDate), int64(m.MentionTimeDate), int(m.MentionType),
+		m.MentionSourceName, m.MentionIdentifier, m.SentenceID, m.Actor1CharOffset, m.Actor2CharOffset,
+		m.ActionCharOffset, m.InRawText, m.Confidence, m.MentionDocLen, m.MentionDocTone,
+		m.MentionDocTranslationInfo,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to write mention of event %d: %w", m.GlobalEventID, err)
+	}
+	return nil
+}
+
+func (w *txWriter) writeArticle(ctx context.Context, a *gdelt.Article) error {
+	if err := w.writeArticleRows(ctx, a); err != nil {
+		return fmt.Errorf("failed to write GKG article %q: %w", a.ID, err)
+	}
+	return nil
+}
+
+func (w *txWriter) writeArticleRows(ctx context.Context, a *gdelt.Article) error {
+	err := w.exec(ctx, upsertArticleSQL,
+		a.ID, int64(a.Date), int(a.SourceCollectionIdentifier), a.SourceCommonName, a.DocumentIdentifier,
+		a.Tone.Tone, a.Tone.PositiveScore, a.Tone.NegativeScore, a.Tone.Polarity,
+		a.Tone.ActivityReferenceDensity, a.Tone.SelfGroupReferenceDensity, a.Tone.WordCount,
+		a.SharingImage, a.TranslationInfo.SourceLanguage, a.TranslationInfo.Engine, a.Extras.PageTitle,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range articleChildTables {
+		if err := w.exec(ctx, "DELETE FROM "+table+" WHERE gkg_record_id = ?", a.ID); err != nil {
+			return err
+		}
+	}
+
+	names := []struct {
+		kind     string
+		enhanced []gdelt.NamedOffset
+		plain    []string
+	}{
+		{"theme", a.EnhancedThemes, a.Themes},
+		{"person", a.EnhancedPersons, a.Persons},
+		{"organization", a.EnhancedOrganizations, a.Organizations},
+		{"name", a.AllNames, nil},
+	}
+	for _, n := range names {
+		for i, no := range namedOffsets(n.enhanced, n.plain) {
+			if err := w.exec(ctx, insertArticleNameSQL, a.ID, n.kind, i, no.Name, no.CharOffset); err != nil {
+				return err
+			}
+		}
+	}
+
+	locations := a.EnhancedLocations
+	if len(locations) == 0 {
+		locations = a.Locations
+	}
+	for i, l := range locations {
+		err := w.exec(ctx, insertArticleLocationSQL,
+			a.ID, i, int(l.Type), l.Fullname, l.CountryCode, l.ADM1Code, l.ADM2Code,
+			nullFloat64(l.Lat), nullFloat64(l.Long), l.FeatureID, l.CharOffset,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	for dimension, value := range a.GCAM {
+		if err := w.exec(ctx, insertArticleGCAMSQL, a.ID, dimension, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// namedOffsets returns the enhanced variant of a GKG name list, or the
+// plain one, with -1 offsets, if the former is empty.
+func namedOffsets(enhanced []gdelt.NamedOffset, plain []string) []gdelt.NamedOffset {
+	if len(enhanced) > 0 {
+		return enhanced
+	}
+	result := make([]gdelt.NamedOffset, len(plain))
+	for i, name := range plain {
+		result[i] = gdelt.NamedOffset{Name: name, CharOffset: -1}
+	}
+	return result
+}
+
+func appendActorData(args []any, a gdelt.ActorData) []any {
+	return append(args,
+		a.Code, a.Name, a.CountryCode, a.KnownGroupCode, a.EthnicCode,
+		a.Religion1Code, a.Religion2Code, a.Type1Code, a.Type2Code, a.Type3Code,
+	)
+}
+
+func appendGeoData(args []any, g gdelt.GeoData) []any {
+	return append(args,
+		int(g.Type), g.Fullname, g.CountryCode, g.ADM1Code, g.ADM2Code,
+		nullFloat64(g.Lat), nullFloat64(g.Long), g.FeatureID,
+	)
+}
+
+func nullFloat64(n gdelt.NullableFloat64) sql.NullFloat64 {
+	return sql.NullFloat64{Float64: n.Float64, Valid: n.Valid}
+}
@@ -0,0 +1,199 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdeltsqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/nlpodyssey/gdelt"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "gdelt.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func countRows(t *testing.T, db *sql.DB, table string) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func testBatch() *gdelt.Batch {
+	article := &gdelt.Article{
+		ID:                 "20240614003000-1",
+		Date:               20240614003000,
+		DocumentIdentifier: "https://example.com/story",
+		Themes:             []string{"TAX_FNCACT", "LEADER", "PROTEST"},
+		EnhancedLocations: []gdelt.Location{
+			{GeoData: gdelt.GeoData{Type: gdelt.WorldCity, Fullname: "Kabul, Kabol, Afghanistan", CountryCode: "AF", Lat: gdelt.NullableFloat64{Float64: 34.5167, Valid: true}}, CharOffset: 120},
+			{GeoData: gdelt.GeoData{Type: gdelt.Country, Fullname: "France", CountryCode: "FR"}, CharOffset: 450},
+		},
+		GCAM: map[string]float64{"wc": 512, "c1.1": 3},
+	}
+	events := []*gdelt.Event{
+		{GlobalEventID: 1, Day: 20240614, EventCode: "195", AvgTone: -5, DateAdded: 20240614003000, SourceURL: article.DocumentIdentifier},
+		{GlobalEventID: 2, Day: 20240614, EventCode: "010", AvgTone: 1, DateAdded: 20240614003000, SourceURL: article.DocumentIdentifier},
+	}
+	mentions := []*gdelt.Mention{
+		{GlobalEventID: 1, MentionIdentifier: article.DocumentIdentifier, SentenceID: 1, MentionType: gdelt.WebMention},
+		{GlobalEventID: 2, MentionIdentifier: article.DocumentIdentifier, SentenceID: 4, MentionType: gdelt.WebMention},
+	}
+	return &gdelt.Batch{Events: events, Mentions: mentions, Articles: []*gdelt.Article{article}}
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	for i := 0; i < 2; i++ {
+		if err := Migrate(ctx, db); err != nil {
+			t.Fatalf("Migrate #%d: %v", i+1, err)
+		}
+		var version int
+		if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+			t.Fatal(err)
+		}
+		if version != SchemaVersion {
+			t.Errorf("after Migrate #%d, user_version = %d, want %d", i+1, version, SchemaVersion)
+		}
+	}
+	for _, table := range []string{"events", "mentions", "articles", "article_names", "article_locations", "article_gcam"} {
+		if n := countRows(t, db, table); n != 0 {
+			t.Errorf("%s has %d rows, want 0", table, n)
+		}
+	}
+}
+
+func TestMigrateNewerSchema(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	if _, err := db.Exec("PRAGMA user_version = 1000"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Migrate(ctx, db); err == nil {
+		t.Error("Migrate succeeded on a newer schema, want an error")
+	}
+}
+
+func TestSinkReingest(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	sink, err := NewSink(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := testBatch()
+	for i := 0; i < 2; i++ {
+		if err := sink.WriteBatch(ctx, b); err != nil {
+			t.Fatalf("WriteBatch #%d: %v", i+1, err)
+		}
+	}
+	want := map[string]int{"events": 2, "mentions": 2, "articles": 1, "article_names": 3, "article_locations": 2, "article_gcam": 2}
+	for table, n := range want {
+		if got := countRows(t, db, table); got != n {
+			t.Errorf("%s has %d rows, want %d", table, got, n)
+		}
+	}
+
+	b.Events[0].AvgTone = -7.5
+	if err := sink.WriteEvents(ctx, b.Events[:1]); err != nil {
+		t.Fatal(err)
+	}
+	var tone float64
+	if err := db.QueryRow("SELECT avg_tone FROM events WHERE global_event_id = 1").Scan(&tone); err != nil {
+		t.Fatal(err)
+	}
+	if tone != -7.5 {
+		t.Errorf("avg_tone = %v, want the updated -7.5", tone)
+	}
+	if n := countRows(t, db, "events"); n != 2 {
+		t.Errorf("events has %d rows after the update, want 2", n)
+	}
+}
+
+func TestSinkReplacesArticleChildren(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	sink, err := NewSink(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := testBatch()
+	if err := sink.WriteBatch(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	a := b.Articles[0]
+	a.Themes = []string{"ELECTION"}
+	a.EnhancedLocations = nil
+	a.GCAM = map[string]float64{"wc": 600}
+	if err := sink.WriteBatch(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]int{"article_names": 1, "article_locations": 0, "article_gcam": 1}
+	for table, n := range want {
+		if got := countRows(t, db, table); got != n {
+			t.Errorf("%s has %d rows, want %d", table, got, n)
+		}
+	}
+	var name string
+	if err := db.QueryRow("SELECT name FROM article_names WHERE gkg_record_id = ? AND kind = 'theme'", a.ID).Scan(&name); err != nil {
+		t.Fatal(err)
+	}
+	if name != "ELECTION" {
+		t.Errorf("theme = %q, want %q", name, "ELECTION")
+	}
+	var wc float64
+	if err := db.QueryRow("SELECT value FROM article_gcam WHERE gkg_record_id = ? AND dimension = 'wc'", a.ID).Scan(&wc); err != nil {
+		t.Fatal(err)
+	}
+	if wc != 600 {
+		t.Errorf("wc = %v, want 600", wc)
+	}
+}
+
+func TestSinkInMemory(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	sink, err := NewSink(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.WriteBatch(ctx, testBatch()); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRows(t, db, "events"); n != 2 {
+		t.Errorf("events has %d rows, want 2", n)
+	}
+}
@@ -0,0 +1,439 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// EventIterator streams the events of one or more 15-minute batches,
+// decoding them one row at a time and applying the Opts filter to each.
+//
+// While a batch is being read, the following ones are downloaded and
+// decoded in the background, up to Fetcher.Parallelism batches ahead. Only
+// the GKG articles (and, if requested, the mentions) of these batches are
+// held in memory, so that memory usage does not grow with the total number
+// of batches. Downloaded archives are staged in temporary files.
+//
+// Typical usage:
+//
+//	it := f.EventsBetween(ctx, start, end, opts)
+//	defer it.Close()
+//	for it.Next() {
+//		ev := it.Event()
+//		// ...
+//	}
+//	if err := it.Err(); err != nil {
+//		// ...
+//	}
+type EventIterator struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	f      *Fetcher
+	opts   Opts
+	filter *eventFilter
+
+	list       func(ctx context.Context) ([]*fileReferences, error)
+	listed     bool
+	prefetcher *batchPrefetcher
+	batch      *batchEventReader
+
+	event             *Event
+	err               error
+	attempts          int
+	duplicateArticles int
+}
+
+// LatestEvents returns an iterator over the latest GDELT events.
+func (f *Fetcher) LatestEvents(ctx context.Context, opts Opts) *EventIterator {
+	return f.newEventIterator(ctx, opts, func(ctx context.Context) ([]*fileReferences, error) {
+		return f.latestBatches(ctx, opts.Translingual)
+	})
+}
+
+// EventsBetween returns an iterator over the GDELT events of all batches
+// published between start (inclusive) and end (exclusive).
+func (f *Fetcher) EventsBetween(ctx context.Context, start, end time.Time, opts Opts) *EventIterator {
+	return f.newEventIterator(ctx, opts, func(ctx context.Context) ([]*fileReferences, error) {
+		return f.listBatches(ctx, start, end, opts.Translingual)
+	})
+}
+
+func (f *Fetcher) newEventIterator(ctx context.Context, opts Opts, list func(context.Context) ([]*fileReferences, error)) *EventIterator {
+	ctx, cancel := context.WithCancel(ctx)
+	return &EventIterator{
+		ctx:    ctx,
+		cancel: cancel,
+		f:      f,
+		opts:   opts,
+		filter: newEventFilter(opts),
+		list:   list,
+	}
+}
+
+// Next advances the iterator to the next event that passes the filter,
+// which will then be available through the Event method. It returns false
+// when the iteration stops, either by reaching the end or an error.
+func (it *EventIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if !it.listed {
+		it.listed = true
+		batches, err := it.list(it.ctx)
+		if err != nil {
+			it.fail(err)
+			return false
+		}
+		it.prefetcher = it.f.prefetchBatches(it.ctx, batches, it.opts)
+	}
+
+	for {
+		if err := it.ctx.Err(); err != nil {
+			it.fail(err)
+			return false
+		}
+
+		if it.batch == nil {
+			res, ok := it.nextBatch()
+			if !ok {
+				return false
+			}
+			fr, batch, err := res.fr, res.batch, res.err
+			if IsBadStatusCodeError(err) {
+				// Avoid hard failures because of missing files.
+				it.f.logger().Warn("failed to get GDELT batch events", "batch", fr.Time(), "error", err)
+				continue
+			}
+			if err != nil {
+				it.fail(fmt.Errorf("failed to get events of batch %s: %w", fr.Time().Format(dateAddedTimeLayout), err))
+				return false
+			}
+			it.batch = batch
+		}
+
+		ev, err := it.batch.next()
+		if err == io.EOF {
+			it.attempts += it.batch.attempts
+			it.duplicateArticles += it.batch.duplicateArticles
+			err = it.batch.Close()
+			it.batch = nil
+			if err != nil {
+				it.fail(err)
+				return false
+			}
+			continue
+		}
+		if err != nil {
+			it.fail(err)
+			return false
+		}
+		if !it.filter.accept(ev) {
+			continue
+		}
+		it.event = ev
+		return true
+	}
+}
+
+// Event returns the current event.
+func (it *EventIterator) Event() *Event {
+	return it.event
+}
+
+// Attempts returns the number of data file download attempts made for the
+// batches read so far, including retries.
+func (it *EventIterator) Attempts() int {
+	return it.attempts
+}
+
+// DuplicateArticles returns the number of GKG articles with an already seen
+// DocumentIdentifier found in the batches read so far.
+func (it *EventIterator) DuplicateArticles() int {
+	return it.duplicateArticles
+}
+
+// Err returns the error, if any, that was encountered during iteration.
+func (it *EventIterator) Err() error {
+	return it.err
+}
+
+// Close releases the resources held by the iterator. It is safe to call
+// Close multiple times, and after Next returned false.
+func (it *EventIterator) Close() error {
+	it.cancel()
+	// Wait for the batches being opened, and release them.
+	if it.prefetcher != nil {
+		it.prefetcher.drain()
+	}
+	if it.batch == nil {
+		return nil
+	}
+	err := it.batch.Close()
+	it.batch = nil
+	return err
+}
+
+func (it *EventIterator) fail(err error) {
+	it.err = err
+	_ = it.Close()
+}
+
+// nextBatch waits for the next batch being opened in the background.
+// It returns false when there are no more batches.
+func (it *EventIterator) nextBatch() (openedBatch, bool) {
+	if it.prefetcher == nil {
+		return openedBatch{}, false
+	}
+	return it.prefetcher.next()
+}
+
+// openedBatch is the result of opening a batch in the background.
+type openedBatch struct {
+	fr    *fileReferences
+	batch *batchEventReader
+	err   error
+}
+
+// batchPrefetcher opens batches in the background, delivering them in
+// order. Each batch is opened by its own goroutine, but no more than
+// Fetcher.Parallelism of them are started ahead of the one being read, and
+// the Fetcher bounds the number of concurrent downloads.
+type batchPrefetcher struct {
+	// pending delivers, in order, the results of the batches being opened.
+	pending chan chan openedBatch
+}
+
+// prefetchBatches starts opening the batches in the background. To stop
+// early, cancel ctx and call next until it returns false, closing the
+// batches received.
+func (f *Fetcher) prefetchBatches(ctx context.Context, batches []*fileReferences, opts Opts) *batchPrefetcher {
+	p := &batchPrefetcher{pending: make(chan chan openedBatch, f.parallelism()-1)}
+	go func() {
+		defer close(p.pending)
+		for _, fr := range batches {
+			res := make(chan openedBatch, 1)
+			go func(fr *fileReferences) {
+				batch, err := f.openBatch(ctx, fr, opts)
+				res <- openedBatch{fr: fr, batch: batch, err: err}
+			}(fr)
+			select {
+			case p.pending <- res:
+			case <-ctx.Done():
+				// Release the batch nobody is going to read.
+				if r := <-res; r.batch != nil {
+					_ = r.batch.Close()
+				}
+				return
+			}
+		}
+	}()
+	return p
+}
+
+// next waits for the next batch. It returns false when there are no more
+// batches.
+func (p *batchPrefetcher) next() (openedBatch, bool) {
+	if p.pending == nil {
+		return openedBatch{}, false
+	}
+	res, ok := <-p.pending
+	if !ok {
+		p.pending = nil
+		return openedBatch{}, false
+	}
+	return <-res, true
+}
+
+// drain waits for the remaining batches, closing them.
+func (p *batchPrefetcher) drain() {
+	for {
+		res, ok := p.next()
+		if !ok {
+			return
+		}
+		if res.batch != nil {
+			_ = res.batch.Close()
+		}
+	}
+}
+
+// collectEvents drains the iterator into a slice.
+func collectEvents(it *EventIterator) (_ []*Event, err error) {
+	defer func() {
+		if e := it.Close(); e != nil && err == nil {
+			err = e
+		}
+	}()
+	evs := make([]*Event, 0)
+	for it.Next() {
+		evs = append(evs, it.Event())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return evs, nil
+}
+
+// batchEventReader decodes the events of a single batch, joining each of
+// them with its GKG article and mentions.
+type batchEventReader struct {
+	opts Opts
+	f    *Fetcher
+	// time is the timestamp of the batch, start the time processing began.
+	time  time.Time
+	start time.Time
+	// rows counts the records read, rejected those that were malformed,
+	// and returned the events handed out.
+	rows     int
+	rejected int
+	returned int
+	// attempts counts the download attempts of all the batch files.
+	attempts int
+	mu       sync.Mutex
+	// duplicateArticles counts the GKG articles with an already seen
+	// DocumentIdentifier.
+	duplicateArticles int
+
+	export   *zipEntry
+	r        *eventsCsvReader
+	articles map[string][]*Article
+	mentions map[uint64][]*Mention
+	// articleList and mentionList hold the decoded rows in file order.
+	articleList []*Article
+	mentionList []*Mention
+}
+
+// openBatch downloads the files of a batch concurrently and prepares its
+// events for reading. A few historical batches lack some files: they are
+// simply skipped.
+func (f *Fetcher) openBatch(ctx context.Context, fr *fileReferences, opts Opts) (*batchEventReader, error) {
+	b := &batchEventReader{
+		opts:  opts,
+		f:     f,
+		time:  fr.Time(),
+		start: time.Now(),
+	}
+
+	var parts []func() error
+	if len(fr.GKG.URL) > 0 {
+		parts = append(parts, func() error {
+			ze, err := f.openZipFile(ctx, fr.GKG)
+			if err != nil {
+				return fmt.Errorf("failed to get GKG data: %w", err)
+			}
+			b.addAttempts(ze.attempts)
+			articles, err := f.readArticles(ze, opts)
+			if err != nil {
+				return fmt.Errorf("failed to get GKG data: %w", err)
+			}
+			b.articleList = articles
+			b.articles, b.duplicateArticles, err = indexArticles(articles, opts.DuplicateArticles)
+			return err
+		})
+	}
+	if opts.IncludeMentions && len(fr.Mentions.URL) > 0 {
+		parts = append(parts, func() error {
+			ze, err := f.openZipFile(ctx, fr.Mentions)
+			if err != nil {
+				return fmt.Errorf("failed to get mentions data: %w", err)
+			}
+			b.addAttempts(ze.attempts)
+			mentions, err := f.readMentions(ze, opts)
+			if err != nil {
+				return fmt.Errorf("failed to get mentions data: %w", err)
+			}
+			b.mentionList = mentions
+			b.mentions = indexMentions(mentions)
+			return nil
+		})
+	}
+	parts = append(parts, func() (err error) {
+		b.export, err = f.openZipFile(ctx, fr.Export)
+		if err != nil {
+			return fmt.Errorf("failed to get export data: %w", err)
+		}
+		b.addAttempts(b.export.attempts)
+		return nil
+	})
+
+	err := forEach(len(parts), func(i int) error {
+		return parts[i]()
+	})
+	if err != nil {
+		if b.export != nil {
+			_ = b.export.Close()
+		}
+		return nil, err
+	}
+	b.r = newEventsCsvReader(b.export)
+	b.r.url = fr.Export.URL
+	b.r.validate = opts.Validation != SkipValidation
+	return b, nil
+}
+
+// addAttempts counts the download attempts of one of the batch files.
+func (b *batchEventReader) addAttempts(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.attempts += n
+}
+
+// next returns the next event of the batch, or io.EOF at the end.
+// Malformed rows are reported to the ParseErrorHandler and skipped.
+func (b *batchEventReader) next() (*Event, error) {
+	for {
+		ev, err := b.r.read()
+		if err == io.EOF {
+			b.f.logger().Debug("processed GDELT batch",
+				"batch", b.time,
+				"rows", b.rows,
+				"rejected", b.rejected,
+				"events", b.returned,
+				"attempts", b.attempts,
+				"duplicateArticles", b.duplicateArticles,
+				"duration", time.Since(b.start),
+			)
+			return nil, err
+		}
+		b.rows++
+		if err != nil {
+			if err := b.f.handleParseError(b.opts, err); err != nil {
+				return nil, err
+			}
+			if ev == nil || b.opts.Validation == RejectInvalidEvents {
+				b.rejected++
+				continue
+			}
+		}
+		b.returned++
+		if articles := b.articles[ev.SourceURL]; len(articles) > 0 {
+			ev.GKGArticle = articles[0]
+			if b.opts.DuplicateArticles == KeepAllArticles {
+				ev.GKGArticles = articles
+			}
+		}
+		ev.Mentions = b.mentions[ev.GlobalEventID]
+		return ev, nil
+	}
+}
+
+func (b *batchEventReader) Close() error {
+	return b.export.Close()
+}
@@ -0,0 +1,236 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// MarshalJSON encodes n as a JSON number, or null if n is not valid.
+func (n NullableFloat64) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Float64)
+}
+
+// UnmarshalJSON decodes a JSON number, or null for an invalid value.
+func (n *NullableFloat64) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*n = nullNullableFloat64
+		return nil
+	}
+	if err := json.Unmarshal(data, &n.Float64); err != nil {
+		return fmt.Errorf("gdelt: invalid NullableFloat64 value %s: %w", data, err)
+	}
+	n.Valid = true
+	return nil
+}
+
+// GeoTypeFromString returns the GeoType with the given name, as returned by
+// GeoType.String. The empty string is NoGeoType.
+func GeoTypeFromString(s string) (GeoType, bool) {
+	for g := NoGeoType; g <= WorldState; g++ {
+		if g.String() == s {
+			return g, true
+		}
+	}
+	return 0, false
+}
+
+// MarshalJSON encodes g as its name, such as "WORLDCITY".
+func (g GeoType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(g.String())
+}
+
+// UnmarshalJSON decodes a GeoType name. Numeric codes are accepted as well.
+func (g *GeoType) UnmarshalJSON(data []byte) error {
+	var v GeoType
+	ok := false
+	if s, err := jsonEnumString(data); err == nil {
+		v, ok = GeoTypeFromString(s)
+	} else if i, err := strconv.Atoi(string(data)); err == nil {
+		v, ok = GeoTypeFromInt(i)
+	}
+	if !ok {
+		return fmt.Errorf("gdelt: invalid GeoType value %s", data)
+	}
+	*g = v
+	return nil
+}
+
+// MentionTypeFromString returns the MentionType with the given name, as
+// returned by MentionType.String. The empty string is NoMentionType.
+func MentionTypeFromString(s string) (MentionType, bool) {
+	for t := NoMentionType; t <= NonTextualSourceMention; t++ {
+		if t.String() == s {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+// MarshalJSON encodes t as its name, such as "WEB".
+func (t MentionType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON decodes a MentionType name. Numeric codes are accepted as
+// well.
+func (t *MentionType) UnmarshalJSON(data []byte) error {
+	var v MentionType
+	ok := false
+	if s, err := jsonEnumString(data); err == nil {
+		v, ok = MentionTypeFromString(s)
+	} else if i, err := strconv.Atoi(string(data)); err == nil {
+		v, ok = MentionTypeFromInt(i)
+		if i == 0 {
+			v, ok = NoMentionType, true
+		}
+	}
+	if !ok {
+		return fmt.Errorf("gdelt: invalid MentionType value %s", data)
+	}
+	*t = v
+	return nil
+}
+
+// jsonEnumString decodes data as a JSON string, treating null as empty.
+func jsonEnumString(data []byte) (string, error) {
+	if bytes.Equal(data, []byte("null")) {
+		return "", nil
+	}
+	var s string
+	err := json.Unmarshal(data, &s)
+	return s, err
+}
+
+// jsonTime converts a "YYYYMMDDHHMMSS" value to a time encoded in RFC 3339
+// format, or to nil (null) if v is zero.
+func jsonTime(name string, v uint64) (*time.Time, error) {
+	if v == 0 {
+		return nil, nil
+	}
+	t, err := parseTimeDate(v)
+	if err != nil {
+		return nil, fmt.Errorf("gdelt: invalid %s value %d", name, v)
+	}
+	return &t, nil
+}
+
+// timeDateFromJSON is the inverse of jsonTime.
+func timeDateFromJSON(t *time.Time) uint64 {
+	if t == nil {
+		return 0
+	}
+	v, _ := strconv.ParseUint(t.UTC().Format(dateAddedTimeLayout), 10, 64)
+	return v
+}
+
+// The json* types have the same fields as the types they convert, but none
+// of their methods, so that they can be embedded by the custom JSON
+// encodings below without recursion.
+type (
+	jsonEvent   Event
+	jsonArticle Article
+	jsonMention Mention
+)
+
+// MarshalJSON encodes e as a JSON object, with DateAdded in RFC 3339 format.
+func (e Event) MarshalJSON() ([]byte, error) {
+	dateAdded, err := jsonTime("DateAdded", e.DateAdded)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		jsonEvent
+		DateAdded *time.Time `json:"dateAdded"`
+	}{jsonEvent(e), dateAdded})
+}
+
+// UnmarshalJSON decodes a JSON object encoded by MarshalJSON.
+func (e *Event) UnmarshalJSON(data []byte) error {
+	v := struct {
+		*jsonEvent
+		DateAdded *time.Time `json:"dateAdded"`
+	}{jsonEvent: (*jsonEvent)(e)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	e.DateAdded = timeDateFromJSON(v.DateAdded)
+	return nil
+}
+
+// MarshalJSON encodes a as a JSON object, with Date in RFC 3339 format.
+func (a Article) MarshalJSON() ([]byte, error) {
+	date, err := jsonTime("Date", a.Date)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		jsonArticle
+		Date *time.Time `json:"date"`
+	}{jsonArticle(a), date})
+}
+
+// UnmarshalJSON decodes a JSON object encoded by MarshalJSON.
+func (a *Article) UnmarshalJSON(data []byte) error {
+	v := struct {
+		*jsonArticle
+		Date *time.Time `json:"date"`
+	}{jsonArticle: (*jsonArticle)(a)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	a.Date = timeDateFromJSON(v.Date)
+	return nil
+}
+
+// MarshalJSON encodes m as a JSON object, with EventTimeDate and
+// MentionTimeDate in RFC 3339 format.
+func (m Mention) MarshalJSON() ([]byte, error) {
+	eventTime, err := jsonTime("EventTimeDate", m.EventTimeDate)
+	if err != nil {
+		return nil, err
+	}
+	mentionTime, err := jsonTime("MentionTimeDate", m.MentionTimeDate)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		jsonMention
+		EventTimeDate   *time.Time `json:"eventTimeDate"`
+		MentionTimeDate *time.Time `json:"mentionTimeDate"`
+	}{jsonMention(m), eventTime, mentionTime})
+}
+
+// UnmarshalJSON decodes a JSON object encoded by MarshalJSON.
+func (m *Mention) UnmarshalJSON(data []byte) error {
+	v := struct {
+		*jsonMention
+		EventTimeDate   *time.Time `json:"eventTimeDate"`
+		MentionTimeDate *time.Time `json:"mentionTimeDate"`
+	}{jsonMention: (*jsonMention)(m)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	m.EventTimeDate = timeDateFromJSON(v.EventTimeDate)
+	m.MentionTimeDate = timeDateFromJSON(v.MentionTimeDate)
+	return nil
+}
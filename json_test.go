@@ -0,0 +1,407 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testJSONEvent() *Event {
+	return &Event{
+		GlobalEventID: 1179475420,
+		Day:           20240614,
+		MonthYear:     202406,
+		Year:          2024,
+		FractionDate:  2024.4493,
+		Actor1:        ActorData{Code: "RUSMIL", Name: "RUSSIA", CountryCode: "RUS", Type1Code: "MIL"},
+		IsRootEvent:   1,
+		EventCode:     "195",
+		EventBaseCode: "195",
+		EventRootCode: "19",
+		QuadClass:     4,
+		NumMentions:   10,
+		NumSources:    1,
+		NumArticles:   10,
+		AvgTone:       -5.28455284552845,
+		Actor1Geo:     GeoData{Type: Country, Fullname: "Russia", CountryCode: "RS", ADM1Code: "RS", FeatureID: "RS"},
+		ActionGeo: GeoData{
+			Type:        WorldCity,
+			Fullname:    "Kabul, Kabol, Afghanistan",
+			CountryCode: "AF",
+			ADM1Code:    "AF13",
+			Lat:         NullableFloat64{Float64: 34.5167, Valid: true},
+			Long:        NullableFloat64{Float64: 69.1833, Valid: true},
+			FeatureID:   "-3378435",
+		},
+		DateAdded: 20240614003000,
+		SourceURL: "https://www.example.com/story",
+		GKGArticle: &Article{
+			ID:                         "20240614003000-12",
+			Date:                       20240614003000,
+			SourceCollectionIdentifier: WebMention,
+			SourceCommonName:           "example.com",
+			DocumentIdentifier:         "https://www.example.com/story",
+			Themes:                     []string{"PROTEST"},
+			EnhancedLocations:          []Location{{GeoData: GeoData{Type: Country, Fullname: "Afghanistan", CountryCode: "AF"}, CharOffset: 88}},
+			Tone:                       Tone{Tone: -5.2, WordCount: 466},
+			GCAM:                       map[string]float64{"wc": 466},
+			Extras:                     ArticleExtras{PageTitle: "Clashes in Kabul"},
+		},
+		Mentions: []*Mention{{
+			GlobalEventID:     1179475420,
+			EventTimeDate:     20240614003000,
+			MentionTimeDate:   20240614011500,
+			MentionType:       WebMention,
+			MentionSourceName: "example.com",
+			MentionIdentifier: "https://www.example.com/story",
+			SentenceID:        3,
+			Confidence:        50,
+			MentionDocLen:     1000,
+		}},
+	}
+}
+
+// testJSONEventGolden is the JSON encoding of testJSONEvent, indented.
+const testJSONEventGolden = `{
+	"globalEventId": 1179475420,
+	"day": 20240614,
+	"monthYear": 202406,
+	"year": 2024,
+	"fractionDate": 2024.4493,
+	"actor1": {
+		"code": "RUSMIL",
+		"name": "RUSSIA",
+		"countryCode": "RUS",
+		"knownGroupCode": "",
+		"ethnicCode": "",
+		"religion1Code": "",
+		"religion2Code": "",
+		"type1Code": "MIL",
+		"type2Code": "",
+		"type3Code": ""
+	},
+	"actor2": {
+		"code": "",
+		"name": "",
+		"countryCode": "",
+		"knownGroupCode": "",
+		"ethnicCode": "",
+		"religion1Code": "",
+		"religion2Code": "",
+		"type1Code": "",
+		"type2Code": "",
+		"type3Code": ""
+	},
+	"isRootEvent": 1,
+	"eventCode": "195",
+	"eventBaseCode": "195",
+	"eventRootCode": "19",
+	"quadClass": 4,
+	"goldsteinScale": null,
+	"numMentions": 10,
+	"numSources": 1,
+	"numArticles": 10,
+	"avgTone": -5.28455284552845,
+	"actor1Geo": {
+		"type": "COUNTRY",
+		"fullname": "Russia",
+		"countryCode": "RS",
+		"adm1Code": "RS",
+		"adm2Code": "",
+		"lat": null,
+		"long": null,
+		"featureId": "RS"
+	},
+	"actor2Geo": {
+		"type": "",
+		"fullname": "",
+		"countryCode": "",
+		"adm1Code": "",
+		"adm2Code": "",
+		"lat": null,
+		"long": null,
+		"featureId": ""
+	},
+	"actionGeo": {
+		"type": "WORLDCITY",
+		"fullname": "Kabul, Kabol, Afghanistan",
+		"countryCode": "AF",
+		"adm1Code": "AF13",
+		"adm2Code": "",
+		"lat": 34.5167,
+		"long": 69.1833,
+		"featureId": "-3378435"
+	},
+	"sourceUrl": "https://www.example.com/story",
+	"gkgArticle": {
+		"id": "20240614003000-12",
+		"sourceCollectionIdentifier": "WEB",
+		"sourceCommonName": "example.com",
+		"documentIdentifier": "https://www.example.com/story",
+		"counts": null,
+		"enhancedCounts": null,
+		"themes": [
+			"PROTEST"
+		],
+		"enhancedThemes": null,
+		"locations": null,
+		"enhancedLocations": [
+			{
+				"type": "COUNTRY",
+				"fullname": "Afghanistan",
+				"countryCode": "AF",
+				"adm1Code": "",
+				"adm2Code": "",
+				"lat": null,
+				"long": null,
+				"featureId": "",
+				"charOffset": 88
+			}
+		],
+		"persons": null,
+		"enhancedPersons": null,
+		"organizations": null,
+		"enhancedOrganizations": null,
+		"tone": {
+			"tone": -5.2,
+			"positiveScore": 0,
+			"negativeScore": 0,
+			"polarity": 0,
+			"activityReferenceDensity": 0,
+			"selfGroupReferenceDensity": 0,
+			"wordCount": 466
+		},
+		"dates": null,
+		"gcam": {
+			"wc": 466
+		},
+		"sharingImage": "",
+		"relatedImages": null,
+		"socialImageEmbeds": null,
+		"socialVideoEmbeds": null,
+		"quotations": null,
+		"allNames": null,
+		"amounts": null,
+		"translationInfo": {
+			"sourceLanguage": "",
+			"engine": ""
+		},
+		"extras": {
+			"pageTitle": "Clashes in Kabul"
+		},
+		"date": "2024-06-14T00:30:00Z"
+	},
+	"mentions": [
+		{
+			"globalEventId": 1179475420,
+			"mentionType": "WEB",
+			"mentionSourceName": "example.com",
+			"mentionIdentifier": "https://www.example.com/story",
+			"sentenceId": 3,
+			"actor1CharOffset": 0,
+			"actor2CharOffset": 0,
+			"actionCharOffset": 0,
+			"inRawText": 0,
+			"confidence": 50,
+			"mentionDocLen": 1000,
+			"mentionDocTone": 0,
+			"mentionDocTranslationInfo": "",
+			"eventTimeDate": "2024-06-14T00:30:00Z",
+			"mentionTimeDate": "2024-06-14T01:15:00Z"
+		}
+	],
+	"dateAdded": "2024-06-14T00:30:00Z"
+}`
+
+func TestEventJSON(t *testing.T) {
+	var want bytes.Buffer
+	if err := json.Compact(&want, []byte(testJSONEventGolden)); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(testJSONEvent())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, want.Bytes()) {
+		t.Errorf("expected\n%s\nactual\n%s", want.Bytes(), data)
+	}
+
+	got := new(Event)
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, testJSONEvent()) {
+		t.Errorf("round trip: expected %+v, actual %+v", testJSONEvent(), got)
+	}
+}
+
+func TestEventJSONNullTimes(t *testing.T) {
+	data, err := json.Marshal(Event{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"dateAdded":null`) {
+		t.Errorf("expected a null dateAdded, actual %s", data)
+	}
+	if _, err := json.Marshal(Event{DateAdded: 20241399000000}); err == nil {
+		t.Error("expected an error for an invalid DateAdded")
+	}
+}
+
+func TestNullableFloat64JSON(t *testing.T) {
+	tests := []struct {
+		value NullableFloat64
+		json  string
+	}{
+		{NullableFloat64{}, "null"},
+		{NullableFloat64{Float64: 0, Valid: true}, "0"},
+		{NullableFloat64{Float64: -10, Valid: true}, "-10"},
+		{NullableFloat64{Float64: 34.5167, Valid: true}, "34.5167"},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.value)
+		if err != nil || string(data) != tt.json {
+			t.Errorf("Marshal(%+v) = %s, %v, expected %s", tt.value, data, err, tt.json)
+		}
+		var got NullableFloat64
+		if err := json.Unmarshal([]byte(tt.json), &got); err != nil || got != tt.value {
+			t.Errorf("Unmarshal(%s) = %+v, %v, expected %+v", tt.json, got, err, tt.value)
+		}
+	}
+	var n NullableFloat64
+	if err := json.Unmarshal([]byte(`"1.5"`), &n); err == nil {
+		t.Error("expected an error for a JSON string")
+	}
+}
+
+func TestGeoTypeJSON(t *testing.T) {
+	names := map[GeoType]string{
+		NoGeoType:  `""`,
+		Country:    `"COUNTRY"`,
+		USState:    `"USSTATE"`,
+		USCity:     `"USCITY"`,
+		WorldCity:  `"WORLDCITY"`,
+		WorldState: `"WORLDSTATE"`,
+	}
+	for g, name := range names {
+		data, err := json.Marshal(g)
+		if err != nil || string(data) != name {
+			t.Errorf("Marshal(%d) = %s, %v, expected %s", g, data, err, name)
+		}
+		var got GeoType
+		if err := json.Unmarshal([]byte(name), &got); err != nil || got != g {
+			t.Errorf("Unmarshal(%s) = %d, %v, expected %d", name, got, err, g)
+		}
+	}
+
+	tests := []struct {
+		json  string
+		want  GeoType
+		valid bool
+	}{
+		{"4", WorldCity, true},
+		{"0", NoGeoType, true},
+		{"null", NoGeoType, true},
+		{"6", 0, false},
+		{`"worldcity"`, 0, false},
+		{`"TOWN"`, 0, false},
+	}
+	for _, tt := range tests {
+		var got GeoType
+		err := json.Unmarshal([]byte(tt.json), &got)
+		if (err == nil) != tt.valid || got != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, %v, expected %d, valid %v", tt.json, got, err, tt.want, tt.valid)
+		}
+	}
+}
+
+func TestMentionTypeJSON(t *testing.T) {
+	tests := []struct {
+		json  string
+		want  MentionType
+		valid bool
+	}{
+		{`"WEB"`, WebMention, true},
+		{"1", WebMention, true},
+		{`""`, NoMentionType, true},
+		{"0", NoMentionType, true},
+		{"9", 0, false},
+		{`"RADIO"`, 0, false},
+	}
+	for _, tt := range tests {
+		var got MentionType
+		err := json.Unmarshal([]byte(tt.json), &got)
+		if (err == nil) != tt.valid || got != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, %v, expected %d, valid %v", tt.json, got, err, tt.want, tt.valid)
+		}
+	}
+	if data, err := json.Marshal(WebMention); err != nil || string(data) != `"WEB"` {
+		t.Errorf("Marshal(WebMention) = %s, %v", data, err)
+	}
+}
+
+func TestNDJSONRoundTrip(t *testing.T) {
+	second := testJSONEvent()
+	second.GlobalEventID++
+	second.GKGArticle = nil
+	second.Mentions = nil
+	events := []*Event{testJSONEvent(), second}
+
+	var buf bytes.Buffer
+	if err := NewNDJSONWriter(&buf).WriteAll(events); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(events) {
+		t.Fatalf("expected %d lines, actual %d", len(events), len(lines))
+	}
+	for i, e := range events {
+		want, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lines[i] != string(want) {
+			t.Errorf("line %d: expected %s, actual %s", i+1, want, lines[i])
+		}
+	}
+
+	r := NewNDJSONReader(&buf)
+	got, err := r.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, events) {
+		t.Errorf("expected %+v, actual %+v", events, got)
+	}
+	if _, err := r.Read(); err != io.EOF {
+		t.Errorf("expected io.EOF, actual %v", err)
+	}
+}
+
+func TestNDJSONReaderError(t *testing.T) {
+	r := NewNDJSONReader(strings.NewReader(`{"globalEventId":1}` + "\n" + `{"actionGeo":{"type":"TOWN"}}` + "\n"))
+	if e, err := r.Read(); err != nil || e.GlobalEventID != 1 {
+		t.Fatalf("Read = %v, %v", e, err)
+	}
+	if _, err := r.Read(); err == nil || !strings.Contains(err.Error(), "event 2") {
+		t.Errorf("expected an error for event 2, actual %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// MasterFileListURL provides the complete list of English CSV data files
+	// published since GDELT 2.0 started.
+	MasterFileListURL = DefaultBaseURL + masterFileListFile
+
+	// MasterFileListTranslationURL provides the complete list of GDELT
+	// Translingual CSV data files.
+	MasterFileListTranslationURL = DefaultBaseURL + masterFileListTranslationFile
+)
+
+const (
+	masterFileListFile            = "masterfilelist.txt"
+	masterFileListTranslationFile = "masterfilelist-translation.txt"
+)
+
+// BatchInterval is the time span covered by each GDELT 2.0 batch.
+const BatchInterval = 15 * time.Minute
+
+// FetchEventsBetween returns the GDELT events of all batches published
+// between start (inclusive) and end (exclusive), using DefaultFetcher.
+func FetchEventsBetween(start, end time.Time, opts Opts) ([]*Event, error) {
+	return DefaultFetcher.FetchEventsBetween(context.Background(), start, end, opts)
+}
+
+// FetchEventsBetween returns the GDELT events of all batches published
+// between start (inclusive) and end (exclusive).
+//
+// Batches are enumerated from the master file lists, so that gaps in the
+// GDELT publishing history are handled transparently. For long ranges,
+// prefer EventsBetween, which does not hold all events in memory.
+func (f *Fetcher) FetchEventsBetween(ctx context.Context, start, end time.Time, opts Opts) ([]*Event, error) {
+	return collectEvents(f.EventsBetween(ctx, start, end, opts))
+}
+
+// listBatches returns the file references of all batches published between
+// start (inclusive) and end (exclusive), sorted by time. The translation
+// batches are included only if translingual is true.
+func (f *Fetcher) listBatches(ctx context.Context, start, end time.Time, translingual bool) ([]*fileReferences, error) {
+	urls := []string{f.url(masterFileListFile)}
+	if translingual {
+		urls = append(urls, f.url(masterFileListTranslationFile))
+	}
+
+	lists := make([][]*fileReferences, len(urls))
+	err := forEach(len(urls), func(i int) (err error) {
+		lists[i], err = f.getMasterFileList(ctx, urls[i], start, end)
+		if err != nil {
+			return fmt.Errorf("failed to get master file list from %q: %w", urls[i], err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*fileReferences
+	for _, batches := range lists {
+		result = append(result, batches...)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Time().Before(result[j].Time())
+	})
+	return result, nil
+}
+
+func (f *Fetcher) getMasterFileList(ctx context.Context, url string, start, end time.Time) (batches []*fileReferences, err error) {
+	_, err = f.retry(ctx, url, func() (err error) {
+		batches, err = f.downloadMasterFileList(ctx, url, start, end)
+		return err
+	})
+	return batches, err
+}
+
+func (f *Fetcher) downloadMasterFileList(ctx context.Context, url string, start, end time.Time) (_ []*fileReferences, err error) {
+	body, err := f.httpGetBody(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to HTTP get %q: %w", url, err)
+	}
+	defer func() {
+		if e := body.Close(); e != nil && err == nil {
+			err = e
+		}
+	}()
+	return parseMasterFileList(body, start, end, f.logger())
+}
+
+// parseMasterFileList reads the rows of a master file list, keeping only the
+// batches between start (inclusive) and end (exclusive). Batches lacking the
+// export file are discarded.
+func parseMasterFileList(r io.Reader, start, end time.Time, logger *slog.Logger) ([]*fileReferences, error) {
+	batches := make(map[time.Time]*fileReferences)
+
+	scanner := bufio.NewScanner(r)
+	for i := 0; scanner.Scan(); i++ {
+		row := strings.TrimSpace(scanner.Text())
+		if len(row) == 0 {
+			continue
+		}
+		fr, err := parseFileReference(row)
+		if err != nil {
+			// The master lists contain a handful of malformed rows.
+			logger.Warn("failed to parse GDELT master file list row", "row", i, "error", err)
+			continue
+		}
+		if fr.Time.Before(start) || !fr.Time.Before(end) {
+			continue
+		}
+		frs, ok := batches[fr.Time]
+		if !ok {
+			frs = new(fileReferences)
+			batches[fr.Time] = frs
+		}
+		if err := frs.set(fr); err != nil {
+			logger.Warn("failed to parse GDELT master file list row", "row", i, "error", err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read master file list: %w", err)
+	}
+
+	result := make([]*fileReferences, 0, len(batches))
+	for _, frs := range batches {
+		if len(frs.Export.URL) == 0 {
+			continue
+		}
+		result = append(result, frs)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Time().Before(result[j].Time())
+	})
+	return result, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import "time"
+
+// Mention records a single mention of an event in a document. Each event
+// is mentioned at least once, by the document referenced in its SourceURL,
+// and may be mentioned by many other documents over time.
+type Mention struct {
+	// GlobalEventID is the ID of the event that was mentioned.
+	GlobalEventID uint64 `json:"globalEventId"`
+	// EventTimeDate is the 15-minute timestamp, in "YYYYMMDDHHMMSS" format,
+	// of when the event was first recorded. It matches Event.DateAdded.
+	EventTimeDate uint64 `json:"eventTimeDate"`
+	// MentionTimeDate is the 15-minute timestamp, in "YYYYMMDDHHMMSS" format,
+	// of the batch in which this mention was processed.
+	MentionTimeDate uint64 `json:"mentionTimeDate"`
+	// MentionType identifies the source collection the document came from.
+	MentionType MentionType `json:"mentionType"`
+	// MentionSourceName is a human-friendly identifier of the source of the
+	// document, such as the top-level domain of a web page.
+	MentionSourceName string `json:"mentionSourceName"`
+	// MentionIdentifier is the unique external identifier of the source
+	// document. For web documents this is the full URL.
+	MentionIdentifier string `json:"mentionIdentifier"`
+	// SentenceID is the sentence within the article where the event was
+	// mentioned, starting with 1 for the first sentence.
+	SentenceID int `json:"sentenceId"`
+	// Actor1CharOffset is the location within the article, in terms of
+	// English characters, where Actor1 was found. It is -1 if Actor1 was not
+	// found in the article.
+	Actor1CharOffset int `json:"actor1CharOffset"`
+	// Actor2CharOffset is the location within the article, in terms of
+	// English characters, where Actor2 was found. It is -1 if Actor2 was not
+	// found in the article.
+	Actor2CharOffset int `json:"actor2CharOffset"`
+	// ActionCharOffset is the location within the article, in terms of
+	// English characters, where the core Action description was found.
+	ActionCharOffset int `json:"actionCharOffset"`
+	// InRawText is 1 if the event was found in the original unaltered raw
+	// article text, and 0 if it required extensive grammatical restructuring.
+	InRawText int `json:"inRawText"`
+	// Confidence is the percent confidence, from 10 to 100, in the extraction
+	// of this event from this article.
+	Confidence int `json:"confidence"`
+	// MentionDocLen is the length in English characters of the source
+	// document.
+	MentionDocLen int `json:"mentionDocLen"`
+	// MentionDocTone is the average tone of the document as a whole.
+	MentionDocTone float64 `json:"mentionDocTone"`
+	// MentionDocTranslationInfo records provenance information for documents
+	// that were machine translated. It is empty for English documents.
+	MentionDocTranslationInfo string `json:"mentionDocTranslationInfo"`
+}
+
+// EventTime converts EventTimeDate int value to time.Time.
+func (m *Mention) EventTime() (time.Time, error) {
+	return parseTimeDate(m.EventTimeDate)
+}
+
+// MentionTime converts MentionTimeDate int value to time.Time.
+func (m *Mention) MentionTime() (time.Time, error) {
+	return parseTimeDate(m.MentionTimeDate)
+}
+
+// MentionType identifies the source collection a mention document came from.
+type MentionType uint8
+
+const (
+	NoMentionType MentionType = iota
+	WebMention
+	CitationOnlyMention
+	CoreMention
+	DTICMention
+	JSTORMention
+	NonTextualSourceMention
+)
+
+func MentionTypeFromInt(value int) (MentionType, bool) {
+	if value < 1 || value > 6 {
+		return 0, false
+	}
+	return MentionType(value), true
+}
+
+func (t MentionType) String() string {
+	switch t {
+	case WebMention:
+		return "WEB"
+	case CitationOnlyMention:
+		return "CITATIONONLY"
+	case CoreMention:
+		return "CORE"
+	case DTICMention:
+		return "DTIC"
+	case JSTORMention:
+		return "JSTOR"
+	case NonTextualSourceMention:
+		return "NONTEXTUALSOURCE"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// NDJSONWriter writes events as newline-delimited JSON, one event per line,
+// as encoded by Event.MarshalJSON.
+type NDJSONWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+// NewNDJSONWriter returns a new NDJSONWriter writing to w.
+func NewNDJSONWriter(w io.Writer) *NDJSONWriter {
+	bw := bufio.NewWriter(w)
+	return &NDJSONWriter{w: bw, enc: json.NewEncoder(bw)}
+}
+
+// Write writes a single event. Writes are buffered, so Flush must
+// eventually be called to ensure that the event is written.
+func (w *NDJSONWriter) Write(e *Event) error {
+	return w.enc.Encode(e)
+}
+
+// WriteAll writes multiple events and then calls Flush.
+func (w *NDJSONWriter) WriteAll(events []*Event) error {
+	for _, e := range events {
+		if err := w.Write(e); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (w *NDJSONWriter) Flush() error {
+	return w.w.Flush()
+}
+
+// NDJSONReader reads events written by NDJSONWriter.
+type NDJSONReader struct {
+	dec  *json.Decoder
+	line int
+}
+
+// NewNDJSONReader returns a new NDJSONReader reading from r.
+func NewNDJSONReader(r io.Reader) *NDJSONReader {
+	return &NDJSONReader{dec: json.NewDecoder(r)}
+}
+
+// Read reads the next event. At the end of the input, it returns nil and
+// io.EOF.
+func (r *NDJSONReader) Read() (*Event, error) {
+	if !r.dec.More() {
+		return nil, io.EOF
+	}
+	r.line++
+	e := new(Event)
+	if err := r.dec.Decode(e); err != nil {
+		return nil, fmt.Errorf("gdelt: failed to decode NDJSON event %d: %w", r.line, err)
+	}
+	return e, nil
+}
+
+// ReadAll reads all the remaining events.
+func (r *NDJSONReader) ReadAll() ([]*Event, error) {
+	var events []*Event
+	for {
+		e, err := r.Read()
+		if err == io.EOF {
+			return events, nil
+		}
+		if err != nil {
+			return events, err
+		}
+		events = append(events, e)
+	}
+}
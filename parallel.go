@@ -0,0 +1,73 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// DefaultParallelism is the maximum number of data files a Fetcher downloads
+// at the same time, unless Fetcher.Parallelism says otherwise.
+const DefaultParallelism = 4
+
+// downloadSlots bounds the number of concurrent data file downloads of
+// a Fetcher.
+type downloadSlots struct {
+	once sync.Once
+	ch   chan struct{}
+}
+
+func (f *Fetcher) parallelism() int {
+	if f.Parallelism > 0 {
+		return f.Parallelism
+	}
+	return DefaultParallelism
+}
+
+// acquireDownloadSlot blocks until a download slot is available, or the
+// context is done.
+func (f *Fetcher) acquireDownloadSlot(ctx context.Context) error {
+	f.slots.once.Do(func() {
+		f.slots.ch = make(chan struct{}, f.parallelism())
+	})
+	select {
+	case f.slots.ch <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (f *Fetcher) releaseDownloadSlot() {
+	<-f.slots.ch
+}
+
+// forEach calls fn concurrently for each index in [0, n), waits for all
+// the calls to return, and joins their errors in index order.
+func forEach(n int, fn func(i int) error) error {
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = fn(i)
+		}(i)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,271 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ParseError describes a malformed record of a GDELT data file.
+// It can be wrapped and recognized using errors.As.
+type ParseError struct {
+	// URL is the URL or local path of the file, if known.
+	URL string
+	// Row is the 1-based number of the record within the file.
+	Row int
+	// Column is the 0-based index of the offending column, or -1 if the
+	// error concerns the record as a whole.
+	Column int
+	// ColumnName is the name of the offending column, as documented in the
+	// GDELT codebooks.
+	ColumnName string
+	// Value is the raw value of the offending column.
+	Value string
+	// Err is the underlying error.
+	Err error
+}
+
+// maxParseErrorValueLength limits the length of the raw value included in
+// the error message, since some GKG columns can be very long.
+const maxParseErrorValueLength = 64
+
+func (err *ParseError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("parse error")
+	if len(err.URL) > 0 {
+		fmt.Fprintf(&sb, " in %q", err.URL)
+	}
+	if err.Row > 0 {
+		fmt.Fprintf(&sb, " at row %d", err.Row)
+	}
+	if err.Column >= 0 {
+		fmt.Fprintf(&sb, " column %d (%s)", err.Column, err.ColumnName)
+		value := err.Value
+		if len(value) > maxParseErrorValueLength {
+			value = value[:maxParseErrorValueLength] + "..."
+		}
+		fmt.Fprintf(&sb, " value %q", value)
+	}
+	fmt.Fprintf(&sb, ": %v", err.Err)
+	return sb.String()
+}
+
+func (err *ParseError) Unwrap() error {
+	return err.Err
+}
+
+func newColumnError(columnNames []string, record []string, i int, err error) *ParseError {
+	return &ParseError{
+		Column:     i,
+		ColumnName: columnNames[i],
+		Value:      record[i],
+		Err:        err,
+	}
+}
+
+func newRecordError(url string, row int, err error) *ParseError {
+	return &ParseError{URL: url, Row: row, Column: -1, Err: err}
+}
+
+// withPosition sets the file URL and row of a *ParseError, or wraps any
+// other error into a record-level *ParseError.
+func withPosition(err error, url string, row int) *ParseError {
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		return newRecordError(url, row, err)
+	}
+	pe.URL = url
+	pe.Row = row
+	return pe
+}
+
+// ParseErrorHandler is called for each malformed record. Returning nil
+// skips the record and continues processing, while returning an error stops
+// the fetch, which fails with that error.
+//
+// Malformed subfields of GKG articles, such as GCAM or Amounts, are reported
+// as well, but returning nil only drops the subfield: the rest of the
+// article is kept.
+type ParseErrorHandler func(err *ParseError) error
+
+// FailOnParseError is a ParseErrorHandler that stops at the first malformed
+// record.
+func FailOnParseError(err *ParseError) error {
+	return err
+}
+
+// ParseErrorReport collects parse errors. Its Handle method can be used as
+// a ParseErrorHandler. It is safe for concurrent use.
+type ParseErrorReport struct {
+	mu     sync.Mutex
+	errors []*ParseError
+}
+
+// Handle records the error and lets processing continue.
+func (r *ParseErrorReport) Handle(err *ParseError) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors = append(r.errors, err)
+	return nil
+}
+
+// Errors returns the collected errors, in order of occurrence.
+func (r *ParseErrorReport) Errors() []*ParseError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*ParseError(nil), r.errors...)
+}
+
+// CountByURL returns the number of rejected records per file.
+func (r *ParseErrorReport) CountByURL() map[string]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := make(map[string]int)
+	for _, err := range r.errors {
+		counts[err.URL]++
+	}
+	return counts
+}
+
+// eventColumns are the names of the GDELT 2.0 export table columns.
+var eventColumns = []string{
+	"GlobalEventID",
+	"Day",
+	"MonthYear",
+	"Year",
+	"FractionDate",
+	"Actor1Code",
+	"Actor1Name",
+	"Actor1CountryCode",
+	"Actor1KnownGroupCode",
+	"Actor1EthnicCode",
+	"Actor1Religion1Code",
+	"Actor1Religion2Code",
+	"Actor1Type1Code",
+	"Actor1Type2Code",
+	"Actor1Type3Code",
+	"Actor2Code",
+	"Actor2Name",
+	"Actor2CountryCode",
+	"Actor2KnownGroupCode",
+	"Actor2EthnicCode",
+	"Actor2Religion1Code",
+	"Actor2Religion2Code",
+	"Actor2Type1Code",
+	"Actor2Type2Code",
+	"Actor2Type3Code",
+	"IsRootEvent",
+	"EventCode",
+	"EventBaseCode",
+	"EventRootCode",
+	"QuadClass",
+	"GoldsteinScale",
+	"NumMentions",
+	"NumSources",
+	"NumArticles",
+	"AvgTone",
+	"Actor1Geo_Type",
+	"Actor1Geo_FullName",
+	"Actor1Geo_CountryCode",
+	"Actor1Geo_ADM1Code",
+	"Actor1Geo_ADM2Code",
+	"Actor1Geo_Lat",
+	"Actor1Geo_Long",
+	"Actor1Geo_FeatureID",
+	"Actor2Geo_Type",
+	"Actor2Geo_FullName",
+	"Actor2Geo_CountryCode",
+	"Actor2Geo_ADM1Code",
+	"Actor2Geo_ADM2Code",
+	"Actor2Geo_Lat",
+	"Actor2Geo_Long",
+	"Actor2Geo_FeatureID",
+	"ActionGeo_Type",
+	"ActionGeo_FullName",
+	"ActionGeo_CountryCode",
+	"ActionGeo_ADM1Code",
+	"ActionGeo_ADM2Code",
+	"ActionGeo_Lat",
+	"ActionGeo_Long",
+	"ActionGeo_FeatureID",
+	"DATEADDED",
+	"SOURCEURL",
+}
+
+// eventColumnsV1 are the names of the GDELT 1.0 export table columns,
+// which lack the geographic ADM2Code columns.
+var eventColumnsV1 = func() []string {
+	columns := make([]string, 0, 58)
+	for _, c := range eventColumns {
+		if !strings.HasSuffix(c, "_ADM2Code") {
+			columns = append(columns, c)
+		}
+	}
+	return columns
+}()
+
+// mentionColumns are the names of the GDELT 2.0 mentions table columns.
+var mentionColumns = []string{
+	"GlobalEventID",
+	"EventTimeDate",
+	"MentionTimeDate",
+	"MentionType",
+	"MentionSourceName",
+	"MentionIdentifier",
+	"SentenceID",
+	"Actor1CharOffset",
+	"Actor2CharOffset",
+	"ActionCharOffset",
+	"InRawText",
+	"Confidence",
+	"MentionDocLen",
+	"MentionDocTone",
+	"MentionDocTranslationInfo",
+	"Extras",
+}
+
+// gkgColumns are the names of the GKG 2.1 table columns.
+var gkgColumns = []string{
+	"GKGRECORDID",
+	"V2.1DATE",
+	"V2SOURCECOLLECTIONIDENTIFIER",
+	"V2SOURCECOMMONNAME",
+	"V2DOCUMENTIDENTIFIER",
+	"V1COUNTS",
+	"V2.1COUNTS",
+	"V1THEMES",
+	"V2ENHANCEDTHEMES",
+	"V1LOCATIONS",
+	"V2ENHANCEDLOCATIONS",
+	"V1PERSONS",
+	"V2ENHANCEDPERSONS",
+	"V1ORGANIZATIONS",
+	"V2ENHANCEDORGANIZATIONS",
+	"V1.5TONE",
+	"V2.1ENHANCEDDATES",
+	"V2GCAM",
+	"V2.1SHARINGIMAGE",
+	"V2.1RELATEDIMAGES",
+	"V2.1SOCIALIMAGEEMBEDS",
+	"V2.1SOCIALVIDEOEMBEDS",
+	"V2.1QUOTATIONS",
+	"V2.1ALLNAMES",
+	"V2.1AMOUNTS",
+	"V2.1TRANSLATIONINFO",
+	"V2EXTRASXML",
+}
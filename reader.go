@@ -17,55 +17,125 @@ package gdelt
 import (
 	"encoding/csv"
 	"fmt"
+	"html"
 	"io"
+	"regexp"
 	"strconv"
+	"strings"
 )
 
 type eventsCsvReader struct {
 	r *csv.Reader
+	// url is the URL or path of the file being read, reported in errors.
+	url string
+	row int
+	// validate enables Event.Validate on each event.
+	validate bool
 }
 
 func newEventsCsvReader(r io.Reader) *eventsCsvReader {
 	csvReader := csv.NewReader(r)
 	csvReader.Comma = '\t'
+	csvReader.LazyQuotes = true
 	return &eventsCsvReader{r: csvReader}
 }
 
+// read reads the next event. Malformed records are reported as *ParseError.
+// If validate is true, invalid events are also reported as *ParseError, and
+// returned along with the error.
 func (r *eventsCsvReader) read() (*Event, error) {
 	csvRecord, err := r.r.Read()
+	if err == io.EOF {
+		return nil, err
+	}
+	r.row++
+	if err != nil {
+		return nil, newRecordError(r.url, r.row, err)
+	}
+	event, err := makeEvent(csvRecord)
 	if err != nil {
-		return nil, err // This includes io.EOF
+		return nil, withPosition(err, r.url, r.row)
+	}
+	if r.validate {
+		if err := event.Validate(); err != nil {
+			return event, newRecordError(r.url, r.row, err)
+		}
+	}
+	return event, nil
+}
+
+// eventLayout describes the columns of one of the export table formats.
+type eventLayout struct {
+	columns []string
+	// geoColumns is the number of columns of each geographic block, which
+	// lack the ADM2Code before GDELT 2.0.
+	geoColumns int
+	// dateOnly is true if DATEADDED has no time component (YYYYMMDD).
+	dateOnly bool
+	// sourceURL is the index of the SOURCEURL column, or -1 if missing.
+	sourceURL int
+}
+
+var (
+	// eventLayoutV2 is the GDELT 2.0 export format.
+	eventLayoutV2 = &eventLayout{columns: eventColumns, geoColumns: 8, sourceURL: 60}
+	// eventLayoutV1 is the GDELT 1.0 daily export format, from April 2013.
+	eventLayoutV1 = &eventLayout{columns: eventColumnsV1, geoColumns: 7, dateOnly: true, sourceURL: 57}
+	// eventLayoutV1Historical is the GDELT 1.0 historical backfile format,
+	// from 1979 to March 2013, which lacks the SOURCEURL.
+	eventLayoutV1Historical = &eventLayout{columns: eventColumnsV1[:57], geoColumns: 7, dateOnly: true, sourceURL: -1}
+)
+
+// detectEventLayout returns the layout of an export record, based on its
+// number of columns.
+func detectEventLayout(csvRecord []string) (*eventLayout, error) {
+	switch len(csvRecord) {
+	case 61:
+		return eventLayoutV2, nil
+	case 58:
+		return eventLayoutV1, nil
+	case 57:
+		return eventLayoutV1Historical, nil
+	default:
+		return nil, fmt.Errorf("expected 61 (GDELT 2.0), 58 or 57 (GDELT 1.0) CSV columns, actual %d", len(csvRecord))
 	}
+}
 
-	if len(csvRecord) != 61 {
-		return nil, fmt.Errorf("expected 61 CSV columns, actual %d", len(csvRecord))
+// makeEvent decodes an export record of any of the supported formats.
+// GDELT 1.0 records leave ADM2Code empty, and their DateAdded is set to
+// midnight of the day the event was added.
+func makeEvent(csvRecord []string) (_ *Event, err error) {
+	layout, err := detectEventLayout(csvRecord)
+	if err != nil {
+		return nil, err
 	}
+	columns := layout.columns
 
 	event := &Event{}
 
 	event.GlobalEventID, err = strconv.ParseUint(csvRecord[0], 10, 64)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse GlobalEventID %#v", csvRecord[0])
+		return nil, newColumnError(columns, csvRecord, 0, err)
 	}
 
 	event.Day, err = strconv.Atoi(csvRecord[1])
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse Day %#v", csvRecord[1])
+		return nil, newColumnError(columns, csvRecord, 1, err)
 	}
 
 	event.MonthYear, err = strconv.Atoi(csvRecord[2])
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse MonthYear %#v", csvRecord[2])
+		return nil, newColumnError(columns, csvRecord, 2, err)
 	}
 
 	event.Year, err = strconv.Atoi(csvRecord[3])
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse Year %#v", csvRecord[3])
+		return nil, newColumnError(columns, csvRecord, 3, err)
 	}
 
 	event.FractionDate, err = strconv.ParseFloat(csvRecord[4], 64)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse FractionDate %#v", csvRecord[4])
+		return nil, newColumnError(columns, csvRecord, 4, err)
 	}
 
 	event.Actor1 = readActorData(csvRecord[5:15])
@@ -73,7 +143,7 @@ func (r *eventsCsvReader) read() (*Event, error) {
 
 	event.IsRootEvent, err = strconv.Atoi(csvRecord[25])
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse IsRootEvent %#v", csvRecord[25])
+		return nil, newColumnError(columns, csvRecord, 25, err)
 	}
 
 	event.EventCode = csvRecord[26]
@@ -82,56 +152,65 @@ func (r *eventsCsvReader) read() (*Event, error) {
 
 	event.QuadClass, err = strconv.Atoi(csvRecord[29])
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse QuadClass %#v", csvRecord[29])
+		return nil, newColumnError(columns, csvRecord, 29, err)
 	}
 
 	if len(csvRecord[30]) > 0 {
 		event.GoldsteinScale.Valid = true
 		event.GoldsteinScale.Float64, err = strconv.ParseFloat(csvRecord[30], 64)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse GoldsteinScale %#v", csvRecord[30])
+			return nil, newColumnError(columns, csvRecord, 30, err)
 		}
 	}
 
 	event.NumMentions, err = strconv.Atoi(csvRecord[31])
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse NumMentions %#v", csvRecord[31])
+		return nil, newColumnError(columns, csvRecord, 31, err)
 	}
 
 	event.NumSources, err = strconv.Atoi(csvRecord[32])
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse NumSources %#v", csvRecord[32])
+		return nil, newColumnError(columns, csvRecord, 32, err)
 	}
 
 	event.NumArticles, err = strconv.Atoi(csvRecord[33])
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse NumArticles %#v", csvRecord[33])
+		return nil, newColumnError(columns, csvRecord, 33, err)
 	}
 
 	event.AvgTone, err = strconv.ParseFloat(csvRecord[34], 64)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse AvgTone %#v", csvRecord[34])
+		return nil, newColumnError(columns, csvRecord, 34, err)
 	}
 
-	event.Actor1Geo, err = readGeoData(csvRecord[35:43])
+	i := 35
+	event.Actor1Geo, err = readGeoData(csvRecord, i, layout)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read Actor1Geo: %v", err)
+		return nil, err
 	}
-	event.Actor2Geo, err = readGeoData(csvRecord[43:51])
+	i += layout.geoColumns
+	event.Actor2Geo, err = readGeoData(csvRecord, i, layout)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read Actor2Geo: %v", err)
+		return nil, err
 	}
-	event.ActionGeo, err = readGeoData(csvRecord[51:59])
+	i += layout.geoColumns
+	event.ActionGeo, err = readGeoData(csvRecord, i, layout)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read ActionGeo: %v", err)
+		return nil, err
 	}
+	i += layout.geoColumns
 
-	event.DateAdded, err = strconv.ParseUint(csvRecord[59], 10, 64)
+	event.DateAdded, err = strconv.ParseUint(csvRecord[i], 10, 64)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse DATEADDED %#v", csvRecord[59])
+		return nil, newColumnError(columns, csvRecord, i, err)
+	}
+	if layout.dateOnly {
+		event.DateAdded *= 1000000
 	}
 
-	event.SourceURL = csvRecord[60]
+	if layout.sourceURL >= 0 {
+		event.SourceURL = csvRecord[layout.sourceURL]
+	}
 
 	return event, nil
 }
@@ -150,15 +229,30 @@ func readActorData(csvFields []string) (a ActorData) {
 	return
 }
 
-func readGeoData(csvFields []string) (g GeoData, err error) {
+// readGeoData reads the geographic columns starting at index i.
+func readGeoData(csvRecord []string, i int, layout *eventLayout) (g GeoData, err error) {
+	columns := layout.columns
+	csvFields := csvRecord[i : i+layout.geoColumns]
+	if layout.geoColumns == 7 {
+		// Insert the missing ADM2Code.
+		csvFields = append(csvFields[:4:4], append([]string{""}, csvFields[4:]...)...)
+	}
+	// column returns the index in csvRecord of the j-th GDELT 2.0 field.
+	column := func(j int) int {
+		if layout.geoColumns == 7 && j > 4 {
+			return i + j - 1
+		}
+		return i + j
+	}
+
 	intGeoType, err := strconv.Atoi(csvFields[0])
 	if err != nil {
-		return g, fmt.Errorf("failed to parse Type %#v", csvFields[0])
+		return g, newColumnError(columns, csvRecord, i, err)
 	}
 	var geoTypeOk bool
 	g.Type, geoTypeOk = GeoTypeFromInt(intGeoType)
 	if !geoTypeOk {
-		return g, fmt.Errorf("unexpected GeoType value %d", intGeoType)
+		return g, newColumnError(columns, csvRecord, i, fmt.Errorf("unexpected GeoType value %d", intGeoType))
 	}
 
 	g.Fullname = csvFields[1]
@@ -169,17 +263,558 @@ func readGeoData(csvFields []string) (g GeoData, err error) {
 	if len(csvFields[5]) > 0 {
 		g.Lat, err = ParseNullableFloat64(csvFields[5])
 		if err != nil {
-			return g, fmt.Errorf("failed to parse Lat %#v", csvFields[5])
+			return g, newColumnError(columns, csvRecord, column(5), err)
 		}
 	}
 
 	if len(csvFields[6]) > 0 {
 		g.Long, err = ParseNullableFloat64(csvFields[6])
 		if err != nil {
-			return g, fmt.Errorf("failed to parse Long %#v", csvFields[6])
+			return g, newColumnError(columns, csvRecord, column(6), err)
 		}
 	}
 
 	g.FeatureID = csvFields[7]
 	return
 }
+
+type mentionsCsvReader struct {
+	r *csv.Reader
+	// url is the URL or path of the file being read, reported in errors.
+	url string
+	row int
+}
+
+func newMentionsCsvReader(r io.Reader) *mentionsCsvReader {
+	csvReader := csv.NewReader(r)
+	csvReader.Comma = '\t'
+	csvReader.LazyQuotes = true
+	return &mentionsCsvReader{r: csvReader}
+}
+
+// read reads the next mention. Malformed records are reported as
+// *ParseError.
+func (r *mentionsCsvReader) read() (*Mention, error) {
+	csvRecord, err := r.r.Read()
+	if err == io.EOF {
+		return nil, err
+	}
+	r.row++
+	if err != nil {
+		return nil, newRecordError(r.url, r.row, err)
+	}
+	m, err := makeMention(csvRecord)
+	if err != nil {
+		return nil, withPosition(err, r.url, r.row)
+	}
+	return m, nil
+}
+
+func makeMention(csvRecord []string) (_ *Mention, err error) {
+	if len(csvRecord) != 16 {
+		return nil, fmt.Errorf("expected 16 CSV columns, actual %d", len(csvRecord))
+	}
+
+	m := &Mention{}
+
+	m.GlobalEventID, err = strconv.ParseUint(csvRecord[0], 10, 64)
+	if err != nil {
+		return nil, newColumnError(mentionColumns, csvRecord, 0, err)
+	}
+
+	m.EventTimeDate, err = strconv.ParseUint(csvRecord[1], 10, 64)
+	if err != nil {
+		return nil, newColumnError(mentionColumns, csvRecord, 1, err)
+	}
+
+	m.MentionTimeDate, err = strconv.ParseUint(csvRecord[2], 10, 64)
+	if err != nil {
+		return nil, newColumnError(mentionColumns, csvRecord, 2, err)
+	}
+
+	intMentionType, err := strconv.Atoi(csvRecord[3])
+	if err != nil {
+		return nil, newColumnError(mentionColumns, csvRecord, 3, err)
+	}
+	var mentionTypeOk bool
+	m.MentionType, mentionTypeOk = MentionTypeFromInt(intMentionType)
+	if !mentionTypeOk {
+		return nil, newColumnError(mentionColumns, csvRecord, 3, fmt.Errorf("unexpected MentionType value %d", intMentionType))
+	}
+
+	m.MentionSourceName = csvRecord[4]
+	m.MentionIdentifier = csvRecord[5]
+
+	m.SentenceID, err = strconv.Atoi(csvRecord[6])
+	if err != nil {
+		return nil, newColumnError(mentionColumns, csvRecord, 6, err)
+	}
+
+	m.Actor1CharOffset, err = parseCharOffset(csvRecord[7])
+	if err != nil {
+		return nil, newColumnError(mentionColumns, csvRecord, 7, err)
+	}
+
+	m.Actor2CharOffset, err = parseCharOffset(csvRecord[8])
+	if err != nil {
+		return nil, newColumnError(mentionColumns, csvRecord, 8, err)
+	}
+
+	m.ActionCharOffset, err = parseCharOffset(csvRecord[9])
+	if err != nil {
+		return nil, newColumnError(mentionColumns, csvRecord, 9, err)
+	}
+
+	m.InRawText, err = strconv.Atoi(csvRecord[10])
+	if err != nil {
+		return nil, newColumnError(mentionColumns, csvRecord, 10, err)
+	}
+
+	m.Confidence, err = strconv.Atoi(csvRecord[11])
+	if err != nil {
+		return nil, newColumnError(mentionColumns, csvRecord, 11, err)
+	}
+
+	m.MentionDocLen, err = strconv.Atoi(csvRecord[12])
+	if err != nil {
+		return nil, newColumnError(mentionColumns, csvRecord, 12, err)
+	}
+
+	m.MentionDocTone, err = strconv.ParseFloat(csvRecord[13], 64)
+	if err != nil {
+		return nil, newColumnError(mentionColumns, csvRecord, 13, err)
+	}
+
+	m.MentionDocTranslationInfo = csvRecord[14]
+
+	return m, nil
+}
+
+// parseCharOffset parses a character offset, mapping an empty value to -1.
+func parseCharOffset(value string) (int, error) {
+	if len(value) == 0 {
+		return -1, nil
+	}
+	return strconv.Atoi(value)
+}
+
+type articlesCsvReader struct {
+	r *csv.Reader
+	// url is the URL or path of the file being read, reported in errors.
+	url string
+	row int
+}
+
+func newArticlesCsvReader(r io.Reader) *articlesCsvReader {
+	csvReader := csv.NewReader(r)
+	csvReader.Comma = '\t'
+	csvReader.LazyQuotes = true
+	return &articlesCsvReader{r: csvReader}
+}
+
+// read reads the next GKG article. Malformed records are reported as
+// *ParseError. Only the column count is mandatory: subfields that cannot be
+// parsed are left empty, and reported in fieldErrs along with the article.
+func (r *articlesCsvReader) read() (_ *Article, fieldErrs []*ParseError, err error) {
+	fields, err := r.r.Read()
+	if err == io.EOF {
+		return nil, nil, err
+	}
+	r.row++
+	if err != nil {
+		return nil, nil, newRecordError(r.url, r.row, err)
+	}
+	if len(fields) != 27 {
+		return nil, nil, newRecordError(r.url, r.row, fmt.Errorf("expected 27 CSV columns, actual %d", len(fields)))
+	}
+	a, fieldErrs := makeArticle(fields)
+	for _, fe := range fieldErrs {
+		withPosition(fe, r.url, r.row)
+	}
+	return a, fieldErrs, nil
+}
+
+// makeArticle converts a 27-column GKG record to an Article. Subfields that
+// cannot be parsed are left empty and reported as column errors.
+func makeArticle(fields []string) (*Article, []*ParseError) {
+	var errs []*ParseError
+	check := func(i int, err error) {
+		if err != nil {
+			errs = append(errs, newColumnError(gkgColumns, fields, i, err))
+		}
+	}
+
+	a := new(Article)
+	a.ID = fields[0]
+
+	var err error
+	a.Date, err = strconv.ParseUint(fields[1], 10, 64)
+	check(1, err)
+
+	if len(fields[2]) > 0 {
+		intSourceCollection, err := strconv.Atoi(fields[2])
+		if err == nil {
+			var ok bool
+			a.SourceCollectionIdentifier, ok = MentionTypeFromInt(intSourceCollection)
+			if !ok {
+				err = fmt.Errorf("unexpected V2SourceCollectionIdentifier value %d", intSourceCollection)
+			}
+		}
+		check(2, err)
+	}
+
+	a.SourceCommonName = fields[3]
+	a.DocumentIdentifier = fields[4]
+
+	a.Counts, err = parseCounts(fields[5], false)
+	check(5, err)
+	a.EnhancedCounts, err = parseCounts(fields[6], true)
+	check(6, err)
+
+	a.Themes = splitList(fields[7], ";")
+	a.EnhancedThemes, err = parseNamedOffsets(fields[8])
+	check(8, err)
+
+	a.Locations, err = parseLocations(fields[9], false)
+	check(9, err)
+	a.EnhancedLocations, err = parseLocations(fields[10], true)
+	check(10, err)
+
+	a.Persons = splitList(fields[11], ";")
+	a.EnhancedPersons, err = parseNamedOffsets(fields[12])
+	check(12, err)
+
+	a.Organizations = splitList(fields[13], ";")
+	a.EnhancedOrganizations, err = parseNamedOffsets(fields[14])
+	check(14, err)
+
+	a.Tone, err = parseTone(fields[15])
+	check(15, err)
+
+	a.Dates, err = parseDateMentions(fields[16])
+	check(16, err)
+
+	a.GCAM, err = parseGCAM(fields[17])
+	check(17, err)
+
+	a.SharingImage = strings.TrimSpace(fields[18])
+	a.RelatedImages = splitList(fields[19], ";")
+	a.SocialImageEmbeds = splitList(fields[20], ";")
+	a.SocialVideoEmbeds = splitList(fields[21], ";")
+
+	a.Quotations, err = parseQuotations(fields[22])
+	check(22, err)
+
+	a.AllNames, err = parseNamedOffsets(fields[23])
+	check(23, err)
+
+	a.Amounts, err = parseAmounts(fields[24])
+	check(24, err)
+
+	a.TranslationInfo = parseTranslationInfo(fields[25])
+	a.Extras = parseArticleExtras(fields[26])
+	return a, errs
+}
+
+// splitList splits a delimited GKG list, dropping empty items.
+func splitList(value, sep string) []string {
+	if len(value) == 0 {
+		return nil
+	}
+	items := strings.Split(value, sep)
+	result := items[:0]
+	for _, item := range items {
+		if len(item) > 0 {
+			result = append(result, item)
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+func parseCounts(value string, withOffset bool) ([]Count, error) {
+	blocks := splitList(value, ";")
+	if blocks == nil {
+		return nil, nil
+	}
+	wantFields := 10
+	if withOffset {
+		wantFields = 11
+	}
+	counts := make([]Count, 0, len(blocks))
+	for _, block := range blocks {
+		fields := strings.Split(block, "#")
+		if len(fields) != wantFields {
+			return nil, fmt.Errorf("expected %d count fields, actual %d", wantFields, len(fields))
+		}
+		c := Count{CountType: fields[0], ObjectType: fields[2], CharOffset: -1}
+		var err error
+		c.Number, err = strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Number %#v", fields[1])
+		}
+		// The count location lacks the ADM2Code field.
+		c.Location, err = readGKGGeoData(fields[3], fields[4], fields[5], fields[6], "", fields[7], fields[8], fields[9])
+		if err != nil {
+			return nil, err
+		}
+		if withOffset {
+			c.CharOffset, err = strconv.Atoi(fields[10])
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse CharOffset %#v", fields[10])
+			}
+		}
+		counts = append(counts, c)
+	}
+	return counts, nil
+}
+
+func parseLocations(value string, enhanced bool) ([]Location, error) {
+	blocks := splitList(value, ";")
+	if blocks == nil {
+		return nil, nil
+	}
+	wantFields := 7
+	if enhanced {
+		wantFields = 9
+	}
+	locations := make([]Location, 0, len(blocks))
+	for _, block := range blocks {
+		fields := strings.Split(block, "#")
+		if len(fields) != wantFields {
+			return nil, fmt.Errorf("expected %d location fields, actual %d", wantFields, len(fields))
+		}
+		l := Location{CharOffset: -1}
+		var err error
+		if enhanced {
+			l.GeoData, err = readGKGGeoData(fields[0], fields[1], fields[2], fields[3], fields[4], fields[5], fields[6], fields[7])
+			if err != nil {
+				return nil, err
+			}
+			l.CharOffset, err = strconv.Atoi(fields[8])
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse CharOffset %#v", fields[8])
+			}
+		} else {
+			l.GeoData, err = readGKGGeoData(fields[0], fields[1], fields[2], fields[3], "", fields[4], fields[5], fields[6])
+			if err != nil {
+				return nil, err
+			}
+		}
+		locations = append(locations, l)
+	}
+	return locations, nil
+}
+
+// readGKGGeoData reads the location fields shared by GKG counts and
+// locations. Unlike the events table, an empty or zero Type is allowed.
+func readGKGGeoData(geoType, fullname, countryCode, adm1Code, adm2Code, lat, long, featureID string) (g GeoData, err error) {
+	if len(geoType) > 0 {
+		intGeoType, err := strconv.Atoi(geoType)
+		if err != nil {
+			return g, fmt.Errorf("failed to parse Type %#v", geoType)
+		}
+		var geoTypeOk bool
+		g.Type, geoTypeOk = GeoTypeFromInt(intGeoType)
+		if !geoTypeOk {
+			return g, fmt.Errorf("unexpected GeoType value %d", intGeoType)
+		}
+	}
+
+	g.Fullname = fullname
+	g.CountryCode = countryCode
+	g.ADM1Code = adm1Code
+	g.ADM2Code = adm2Code
+
+	g.Lat, err = ParseNullableFloat64(lat)
+	if err != nil {
+		return g, fmt.Errorf("failed to parse Lat %#v", lat)
+	}
+	g.Long, err = ParseNullableFloat64(long)
+	if err != nil {
+		return g, fmt.Errorf("failed to parse Long %#v", long)
+	}
+
+	g.FeatureID = featureID
+	return
+}
+
+// parseNamedOffsets parses a list of "name,offset" blocks.
+func parseNamedOffsets(value string) ([]NamedOffset, error) {
+	blocks := splitList(value, ";")
+	if blocks == nil {
+		return nil, nil
+	}
+	result := make([]NamedOffset, 0, len(blocks))
+	for _, block := range blocks {
+		// Names may contain commas, the offset always comes last.
+		i := strings.LastIndexByte(block, ',')
+		if i < 0 {
+			return nil, fmt.Errorf("missing offset in %#v", block)
+		}
+		offset, err := strconv.Atoi(block[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CharOffset %#v", block[i+1:])
+		}
+		result = append(result, NamedOffset{Name: block[:i], CharOffset: offset})
+	}
+	return result, nil
+}
+
+func parseTone(value string) (t Tone, err error) {
+	if len(value) == 0 {
+		return
+	}
+	fields := strings.Split(value, ",")
+	if len(fields) != 7 {
+		return t, fmt.Errorf("expected 7 tone fields, actual %d", len(fields))
+	}
+	floats := []*float64{
+		&t.Tone,
+		&t.PositiveScore,
+		&t.NegativeScore,
+		&t.Polarity,
+		&t.ActivityReferenceDensity,
+		&t.SelfGroupReferenceDensity,
+	}
+	for i, f := range floats {
+		*f, err = strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return t, fmt.Errorf("failed to parse tone field %d %#v", i, fields[i])
+		}
+	}
+	// The word count is occasionally written as a float.
+	wc, err := strconv.ParseFloat(fields[6], 64)
+	if err != nil {
+		return t, fmt.Errorf("failed to parse WordCount %#v", fields[6])
+	}
+	t.WordCount = int(wc)
+	return
+}
+
+func parseDateMentions(value string) ([]DateMention, error) {
+	blocks := splitList(value, ";")
+	if blocks == nil {
+		return nil, nil
+	}
+	result := make([]DateMention, 0, len(blocks))
+	for _, block := range blocks {
+		fields := strings.Split(block, "#")
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("expected 5 date fields, actual %d", len(fields))
+		}
+		var d DateMention
+		ints := []*int{&d.Resolution, &d.Month, &d.Day, &d.Year, &d.CharOffset}
+		for i, n := range ints {
+			var err error
+			*n, err = strconv.Atoi(fields[i])
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse date field %d %#v", i, fields[i])
+			}
+		}
+		result = append(result, d)
+	}
+	return result, nil
+}
+
+func parseGCAM(value string) (map[string]float64, error) {
+	blocks := splitList(value, ",")
+	if blocks == nil {
+		return nil, nil
+	}
+	result := make(map[string]float64, len(blocks))
+	for _, block := range blocks {
+		k, v, ok := strings.Cut(block, ":")
+		if !ok {
+			return nil, fmt.Errorf("missing value in %#v", block)
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse value of %q %#v", k, v)
+		}
+		result[k] = f
+	}
+	return result, nil
+}
+
+func parseQuotations(value string) ([]Quotation, error) {
+	blocks := splitList(value, "#")
+	if blocks == nil {
+		return nil, nil
+	}
+	result := make([]Quotation, 0, len(blocks))
+	for _, block := range blocks {
+		// The quote itself may contain the delimiter, so it is kept whole.
+		fields := strings.SplitN(block, "|", 4)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("expected 4 quotation fields, actual %d", len(fields))
+		}
+		q := Quotation{Verb: fields[2], Quote: fields[3]}
+		var err error
+		q.CharOffset, err = strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CharOffset %#v", fields[0])
+		}
+		q.Length, err = strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Length %#v", fields[1])
+		}
+		result = append(result, q)
+	}
+	return result, nil
+}
+
+func parseAmounts(value string) ([]Amount, error) {
+	blocks := splitList(value, ";")
+	if blocks == nil {
+		return nil, nil
+	}
+	result := make([]Amount, 0, len(blocks))
+	for _, block := range blocks {
+		// The object may contain commas, the amount always comes first and
+		// the offset last.
+		i := strings.IndexByte(block, ',')
+		j := strings.LastIndexByte(block, ',')
+		if i < 0 || i == j {
+			return nil, fmt.Errorf("expected 3 amount fields in %#v", block)
+		}
+		amount, err := strconv.ParseFloat(block[:i], 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Amount %#v", block[:i])
+		}
+		offset, err := strconv.Atoi(block[j+1:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CharOffset %#v", block[j+1:])
+		}
+		result = append(result, Amount{Amount: amount, Object: block[i+1 : j], CharOffset: offset})
+	}
+	return result, nil
+}
+
+func parseTranslationInfo(value string) (ti TranslationInfo) {
+	for _, block := range splitList(value, ";") {
+		k, v, _ := strings.Cut(block, ":")
+		switch k {
+		case "srclc":
+			ti.SourceLanguage = v
+		case "eng":
+			ti.Engine = v
+		}
+	}
+	return
+}
+
+var pageTitleRe = regexp.MustCompile(`<PAGE_TITLE>(.*)</PAGE_TITLE>`)
+var spaceRegexp = regexp.MustCompile(`\s`)
+
+func parseArticleExtras(extrasXML string) (ex ArticleExtras) {
+	sm := pageTitleRe.FindStringSubmatch(extrasXML)
+	if len(sm) == 2 {
+		s := html.UnescapeString(sm[1])
+		s = spaceRegexp.ReplaceAllString(s, " ")
+		ex.PageTitle = strings.TrimSpace(s)
+	}
+	return
+}
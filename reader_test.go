@@ -0,0 +1,322 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// gkgTestColumns are the columns of a GKG 2.1 record, as published by
+// GDELT.
+var gkgTestColumns = []string{
+	"20240614003000-12",
+	"20240614003000",
+	"1",
+	"example.com",
+	"https://www.example.com/world/2024/06/14/kabul-protest.html",
+	"KILL#12#protesters#4#Kabul, Kabol, Afghanistan#AF#AF13#34.5167#69.1833#-3378435;",
+	"KILL#12#protesters#4#Kabul, Kabol, Afghanistan#AF#AF13#34.5167#69.1833#-3378435#1520;",
+	"PROTEST;TAX_FNCACT;TAX_FNCACT_PROTESTERS;",
+	"PROTEST,102;TAX_FNCACT_PROTESTERS,115;TAX_FNCACT,115;",
+	"4#Kabul, Kabol, Afghanistan#AF#AF13#34.5167#69.1833#-3378435;1#Afghanistan#AF#AF#33#65#AF",
+	"4#Kabul, Kabol, Afghanistan#AF#AF13#AF13#34.5167#69.1833#-3378435#1402;1#Afghanistan#AF#AF##33#65#AF#88",
+	"john smith",
+	"John Smith,230",
+	"united nations;smith jones and co",
+	"United Nations,1870;Smith, Jones and Co,2010",
+	"-5.28455284552845,1.6260162601626,6.91056910569106,8.53658536585366,22.7642276422764,0,466",
+	"1#0#0#2023#540;3#6#13#2024#60",
+	"wc:466,c1.2:3,c12.1:45,v10.1:-0.27",
+	"https://www.example.com/img/kabul.jpg",
+	"https://www.example.com/img/a.jpg;https://www.example.com/img/b.jpg",
+	"",
+	"https://youtube.com/watch?v=abc;",
+	"495|63||we will not leave until our demands are met#1212|41|said|prices | wages must change",
+	"John Smith,230;United Nations,1870;Smith, Jones and Co,2010",
+	"12,protesters,210;1.5,million dollars, euros,900;",
+	"srclc:fas;eng:GT-FAS 1.0",
+	"<PAGE_AUTHORS>Jane Doe</PAGE_AUTHORS><PAGE_TITLE>Protesters &amp; police clash in Kabul </PAGE_TITLE>",
+}
+
+func kabulGeoData() GeoData {
+	return GeoData{
+		Type:        WorldCity,
+		Fullname:    "Kabul, Kabol, Afghanistan",
+		CountryCode: "AF",
+		ADM1Code:    "AF13",
+		Lat:         NullableFloat64{Float64: 34.5167, Valid: true},
+		Long:        NullableFloat64{Float64: 69.1833, Valid: true},
+		FeatureID:   "-3378435",
+	}
+}
+
+func TestArticlesCsvReader(t *testing.T) {
+	r := newArticlesCsvReader(strings.NewReader(strings.Join(gkgTestColumns, "\t") + "\n"))
+	got, fieldErrs, err := r.read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fieldErrs) > 0 {
+		t.Fatalf("unexpected field errors: %v", fieldErrs)
+	}
+
+	kabul := kabulGeoData()
+	kabulADM2 := kabul
+	kabulADM2.ADM2Code = "AF13"
+	afghanistan := GeoData{
+		Type:        Country,
+		Fullname:    "Afghanistan",
+		CountryCode: "AF",
+		ADM1Code:    "AF",
+		Lat:         NullableFloat64{Float64: 33, Valid: true},
+		Long:        NullableFloat64{Float64: 65, Valid: true},
+		FeatureID:   "AF",
+	}
+	want := &Article{
+		ID:                         "20240614003000-12",
+		Date:                       20240614003000,
+		SourceCollectionIdentifier: WebMention,
+		SourceCommonName:           "example.com",
+		DocumentIdentifier:         "https://www.example.com/world/2024/06/14/kabul-protest.html",
+		Counts:                     []Count{{CountType: "KILL", Number: 12, ObjectType: "protesters", Location: kabul, CharOffset: -1}},
+		EnhancedCounts:             []Count{{CountType: "KILL", Number: 12, ObjectType: "protesters", Location: kabul, CharOffset: 1520}},
+		Themes:                     []string{"PROTEST", "TAX_FNCACT", "TAX_FNCACT_PROTESTERS"},
+		EnhancedThemes:             []NamedOffset{{"PROTEST", 102}, {"TAX_FNCACT_PROTESTERS", 115}, {"TAX_FNCACT", 115}},
+		Locations:                  []Location{{GeoData: kabul, CharOffset: -1}, {GeoData: afghanistan, CharOffset: -1}},
+		EnhancedLocations:          []Location{{GeoData: kabulADM2, CharOffset: 1402}, {GeoData: afghanistan, CharOffset: 88}},
+		Persons:                    []string{"john smith"},
+		EnhancedPersons:            []NamedOffset{{"John Smith", 230}},
+		Organizations:              []string{"united nations", "smith jones and co"},
+		EnhancedOrganizations:      []NamedOffset{{"United Nations", 1870}, {"Smith, Jones and Co", 2010}},
+		Tone: Tone{
+			Tone:                      -5.28455284552845,
+			PositiveScore:             1.6260162601626,
+			NegativeScore:             6.91056910569106,
+			Polarity:                  8.53658536585366,
+			ActivityReferenceDensity:  22.7642276422764,
+			SelfGroupReferenceDensity: 0,
+			WordCount:                 466,
+		},
+		Dates:             []DateMention{{Resolution: 1, Year: 2023, CharOffset: 540}, {Resolution: 3, Month: 6, Day: 13, Year: 2024, CharOffset: 60}},
+		GCAM:              map[string]float64{"wc": 466, "c1.2": 3, "c12.1": 45, "v10.1": -0.27},
+		SharingImage:      "https://www.example.com/img/kabul.jpg",
+		RelatedImages:     []string{"https://www.example.com/img/a.jpg", "https://www.example.com/img/b.jpg"},
+		SocialVideoEmbeds: []string{"https://youtube.com/watch?v=abc"},
+		Quotations: []Quotation{
+			{CharOffset: 495, Length: 63, Quote: "we will not leave until our demands are met"},
+			{CharOffset: 1212, Length: 41, Verb: "said", Quote: "prices | wages must change"},
+		},
+		AllNames:        []NamedOffset{{"John Smith", 230}, {"United Nations", 1870}, {"Smith, Jones and Co", 2010}},
+		Amounts:         []Amount{{Amount: 12, Object: "protesters", CharOffset: 210}, {Amount: 1.5, Object: "million dollars, euros", CharOffset: 900}},
+		TranslationInfo: TranslationInfo{SourceLanguage: "fas", Engine: "GT-FAS 1.0"},
+		Extras:          ArticleExtras{PageTitle: "Protesters & police clash in Kabul"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		gotV, wantV := reflect.ValueOf(*got), reflect.ValueOf(*want)
+		for i := 0; i < gotV.NumField(); i++ {
+			if !reflect.DeepEqual(gotV.Field(i).Interface(), wantV.Field(i).Interface()) {
+				t.Errorf("%s: expected %+v, actual %+v", gotV.Type().Field(i).Name, wantV.Field(i).Interface(), gotV.Field(i).Interface())
+			}
+		}
+	}
+
+	if _, _, err := r.read(); err != io.EOF {
+		t.Errorf("expected io.EOF, actual %v", err)
+	}
+}
+
+func TestArticlesCsvReaderFieldErrors(t *testing.T) {
+	tests := []struct {
+		column int
+		value  string
+		empty  func(a *Article) bool
+	}{
+		{1, "2024-06-14", func(a *Article) bool { return a.Date == 0 }},
+		{2, "9", func(a *Article) bool { return a.SourceCollectionIdentifier == NoMentionType }},
+		{5, "KILL#many#protesters#4#Kabul#AF#AF13#34.5#69.1#-3378435", func(a *Article) bool { return a.Counts == nil }},
+		{6, "KILL#12#protesters#4#Kabul#AF#AF13#34.5#69.1#-3378435", func(a *Article) bool { return a.EnhancedCounts == nil }},
+		{8, "PROTEST", func(a *Article) bool { return a.EnhancedThemes == nil }},
+		{9, "4#Kabul#AF#AF13#north#69.1#-3378435", func(a *Article) bool { return a.Locations == nil }},
+		{10, "9#Kabul#AF#AF13#AF13#34.5#69.1#-3378435#1402", func(a *Article) bool { return a.EnhancedLocations == nil }},
+		{12, "John Smith,two", func(a *Article) bool { return a.EnhancedPersons == nil }},
+		{15, "-5.2,1.6,6.9,8.5,22.7,0", func(a *Article) bool { return a.Tone == Tone{} }},
+		{16, "1#0#0#2023", func(a *Article) bool { return a.Dates == nil }},
+		{17, "wc", func(a *Article) bool { return a.GCAM == nil }},
+		{22, "495|63|said", func(a *Article) bool { return a.Quotations == nil }},
+		{23, "John Smith", func(a *Article) bool { return a.AllNames == nil }},
+		{24, "twelve,protesters,210", func(a *Article) bool { return a.Amounts == nil }},
+	}
+	for _, tt := range tests {
+		t.Run(gkgColumns[tt.column], func(t *testing.T) {
+			columns := append([]string(nil), gkgTestColumns...)
+			columns[tt.column] = tt.value
+			r := newArticlesCsvReader(strings.NewReader(strings.Join(columns, "\t")))
+			r.url = "test.gkg.csv"
+
+			a, fieldErrs, err := r.read()
+			if err != nil {
+				t.Fatalf("unexpected record error: %v", err)
+			}
+			if len(fieldErrs) != 1 {
+				t.Fatalf("expected 1 field error, actual %v", fieldErrs)
+			}
+			fe := fieldErrs[0]
+			if fe.Column != tt.column || fe.ColumnName != gkgColumns[tt.column] || fe.Value != tt.value || fe.Row != 1 || fe.URL != r.url {
+				t.Errorf("unexpected field error %+v", fe)
+			}
+			if !tt.empty(a) {
+				t.Errorf("expected the malformed field to be left empty")
+			}
+			// The other fields are still parsed.
+			if a.ID != gkgTestColumns[0] || len(a.Themes) != 3 || a.Extras.PageTitle == "" {
+				t.Errorf("expected the other fields to be parsed, actual %+v", a)
+			}
+		})
+	}
+}
+
+func TestArticlesCsvReaderColumnCount(t *testing.T) {
+	r := newArticlesCsvReader(strings.NewReader(strings.Join(gkgTestColumns[:26], "\t")))
+	a, _, err := r.read()
+	var pe *ParseError
+	if a != nil || !errors.As(err, &pe) || pe.Column != -1 || pe.Row != 1 {
+		t.Errorf("expected a record *ParseError, actual %v, %v", a, err)
+	}
+}
+
+// GDELT 1.0 export records, whose geographic blocks lack the ADM2Code.
+var (
+	exportV1DailyRow = exportRow(
+		[]string{"286268549", "20140601", "201406", "2014", "2014.4137"},
+		[]string{"AFG", "AFGHANISTAN", "AFG", "", "", "", "", "", "", ""},
+		[]string{"AFGGOV", "AFGHANISTAN", "AFG", "", "", "", "", "GOV", "", ""},
+		[]string{"1", "043", "043", "04", "1", "2.8", "6", "1", "6", "1.84049079754601"},
+		[]string{"4", "Kabul, Kabol, Afghanistan", "AF", "AF13", "34.5167", "69.1833", "-3378435"},
+		[]string{"1", "Afghanistan", "AF", "AF", "33", "65", "AF"},
+		[]string{"4", "Kabul, Kabol, Afghanistan", "AF", "AF13", "34.5167", "69.1833", "-3378435"},
+		[]string{"20140601", "http://www.example.com/news/kabul"},
+	)
+	exportV1HistoricalRow = exportRow(
+		[]string{"1", "19790101", "197901", "1979", "1979.0027"},
+		[]string{"AFR", "AFRICA", "AFR", "", "", "", "", "", "", ""},
+		[]string{"BWA", "BOTSWANA", "BWA", "", "", "", "", "", "", ""},
+		[]string{"1", "042", "042", "04", "1", "1.9", "14", "14", "14", "0"},
+		[]string{"1", "Botswana", "BC", "BC", "-22", "24", "BC"},
+		[]string{"0", "", "", "", "", "", ""},
+		[]string{"1", "Botswana", "BC", "BC", "-22", "24", "BC"},
+		[]string{"20130203"},
+	)
+)
+
+func TestEventReaderLayouts(t *testing.T) {
+	kabul := kabulGeoData()
+	tests := []struct {
+		name      string
+		row       string
+		columns   int
+		id        uint64
+		actionGeo GeoData
+		actor2Geo GeoData
+		dateAdded uint64
+		sourceURL string
+	}{
+		{
+			name:      "2.0",
+			row:       exportTestRows[0],
+			columns:   61,
+			id:        1179475420,
+			actionGeo: GeoData{Type: WorldCity, Fullname: "Kabul, Kabol, Afghanistan", CountryCode: "AF", ADM1Code: "AF13", Lat: kabul.Lat, Long: kabul.Long, FeatureID: "-3378435"},
+			actor2Geo: GeoData{Type: Country, Fullname: "Afghanistan", CountryCode: "AF", ADM1Code: "AF", Lat: NullableFloat64{Float64: 33, Valid: true}, Long: NullableFloat64{Float64: 65, Valid: true}, FeatureID: "AF"},
+			dateAdded: 20240614003000,
+			sourceURL: "https://www.example.com/news/2024/06/14/story.html",
+		},
+		{
+			name:      "1.0 daily",
+			row:       exportV1DailyRow,
+			columns:   58,
+			id:        286268549,
+			actionGeo: kabul,
+			actor2Geo: GeoData{Type: Country, Fullname: "Afghanistan", CountryCode: "AF", ADM1Code: "AF", Lat: NullableFloat64{Float64: 33, Valid: true}, Long: NullableFloat64{Float64: 65, Valid: true}, FeatureID: "AF"},
+			dateAdded: 20140601000000,
+			sourceURL: "http://www.example.com/news/kabul",
+		},
+		{
+			name:      "1.0 historical",
+			row:       exportV1HistoricalRow,
+			columns:   57,
+			id:        1,
+			actionGeo: GeoData{Type: Country, Fullname: "Botswana", CountryCode: "BC", ADM1Code: "BC", Lat: NullableFloat64{Float64: -22, Valid: true}, Long: NullableFloat64{Float64: 24, Valid: true}, FeatureID: "BC"},
+			actor2Geo: GeoData{},
+			dateAdded: 20130203000000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if n := len(strings.Split(tt.row, "\t")); n != tt.columns {
+				t.Fatalf("test row has %d columns, expected %d", n, tt.columns)
+			}
+			e, err := NewEventReader(strings.NewReader(tt.row + "\n")).Read()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e.GlobalEventID != tt.id {
+				t.Errorf("GlobalEventID: expected %d, actual %d", tt.id, e.GlobalEventID)
+			}
+			if e.ActionGeo != tt.actionGeo {
+				t.Errorf("ActionGeo: expected %+v, actual %+v", tt.actionGeo, e.ActionGeo)
+			}
+			if e.Actor2Geo != tt.actor2Geo {
+				t.Errorf("Actor2Geo: expected %+v, actual %+v", tt.actor2Geo, e.Actor2Geo)
+			}
+			if e.DateAdded != tt.dateAdded {
+				t.Errorf("DateAdded: expected %d, actual %d", tt.dateAdded, e.DateAdded)
+			}
+			if e.SourceURL != tt.sourceURL {
+				t.Errorf("SourceURL: expected %q, actual %q", tt.sourceURL, e.SourceURL)
+			}
+		})
+	}
+}
+
+func TestEventReaderLayoutErrors(t *testing.T) {
+	// ActionGeo_Lat is column 53 in GDELT 1.0 records, 56 in 2.0 ones.
+	v1 := strings.Split(exportV1DailyRow, "\t")
+	v1[53] = "north"
+	tests := []struct {
+		name       string
+		row        string
+		column     int
+		columnName string
+	}{
+		{"1.0 ActionGeo_Lat", strings.Join(v1, "\t"), 53, "ActionGeo_Lat"},
+		{"unknown layout", strings.Join(v1[:56], "\t"), -1, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEventReader(strings.NewReader(tt.row + "\n")).Read()
+			var pe *ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("expected a *ParseError, actual %v", err)
+			}
+			if pe.Column != tt.column || pe.ColumnName != tt.columnName || pe.Row != 1 {
+				t.Errorf("unexpected error position %+v", pe)
+			}
+		})
+	}
+}
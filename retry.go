@@ -0,0 +1,193 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how failed downloads are retried, using exponential
+// backoff with jitter.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts per download, including
+	// the first one. Values lower than 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts. Zero means no cap.
+	MaxBackoff time.Duration
+	// Multiplier is the factor the delay grows by after each retry.
+	// Values lower than 1 are treated as 1.
+	Multiplier float64
+	// Jitter is the fraction, from 0 to 1, by which each delay is randomly
+	// increased or decreased.
+	Jitter float64
+	// Retryable reports whether a failed download should be retried.
+	// If nil, IsRetryableError is used.
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy is a sensible RetryPolicy for the public GDELT servers.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    4,
+	InitialBackoff: 2 * time.Second,
+	MaxBackoff:     time.Minute,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+// IntegrityError indicates that a downloaded file does not match the size
+// or MD5 sum announced by the GDELT file lists, usually because of a
+// truncated response.
+type IntegrityError struct {
+	URL string
+	Err error
+}
+
+func (err *IntegrityError) Error() string {
+	return fmt.Sprintf("failed to validate %q: %v", err.URL, err.Err)
+}
+
+func (err *IntegrityError) Unwrap() error {
+	return err.Err
+}
+
+// RetryError is returned when a download still fails after more than one
+// attempt. It wraps the error of the last attempt.
+type RetryError struct {
+	URL      string
+	Attempts int
+	Err      error
+}
+
+func (err *RetryError) Error() string {
+	return fmt.Sprintf("giving up on %q after %d attempts: %v", err.URL, err.Attempts, err.Err)
+}
+
+func (err *RetryError) Unwrap() error {
+	return err.Err
+}
+
+// IsRetryableError reports whether err is likely transient: timeouts,
+// connection failures and resets, truncated or corrupted downloads, and
+// HTTP status codes 408, 429 and 5xx.
+func IsRetryableError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var sce BadStatusCodeError
+	if errors.As(err, &sce) {
+		return sce.StatusCode == http.StatusRequestTimeout ||
+			sce.StatusCode == http.StatusTooManyRequests ||
+			sce.StatusCode >= 500
+	}
+	var ie *IntegrityError
+	if errors.As(err, &ie) {
+		return true
+	}
+	// Client errors such as malformed URLs, invalid certificates and
+	// rejected redirects are net.Error values too, but they are permanent.
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return true
+	}
+	var oe *net.OpError
+	return errors.As(err, &oe) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	if p.Retryable == nil {
+		return IsRetryableError(err)
+	}
+	return p.Retryable(err)
+}
+
+// backoff returns the delay before the given retry, starting from 1.
+// A longer delay requested by the server is honoured.
+func (p *RetryPolicy) backoff(retry int, err error) time.Duration {
+	multiplier := math.Max(p.Multiplier, 1)
+	d := float64(p.InitialBackoff) * math.Pow(multiplier, float64(retry-1))
+	if p.MaxBackoff > 0 {
+		d = math.Min(d, float64(p.MaxBackoff))
+	}
+	d += (rand.Float64()*2 - 1) * p.Jitter * d
+
+	delay := time.Duration(d)
+	var sce BadStatusCodeError
+	if errors.As(err, &sce) && sce.RetryAfter > delay {
+		delay = sce.RetryAfter
+	}
+	return delay
+}
+
+// retry calls op until it succeeds or the fetcher's RetryPolicy gives up,
+// returning the number of attempts made. If more than one attempt was made
+// and all failed, the error is a *RetryError.
+func (f *Fetcher) retry(ctx context.Context, url string, op func() error) (attempts int, err error) {
+	p := f.Retry
+	for attempts = 1; ; attempts++ {
+		err = op()
+		if err == nil {
+			return attempts, nil
+		}
+		if p == nil || attempts >= p.MaxAttempts || !p.retryable(err) || ctx.Err() != nil {
+			break
+		}
+
+		delay := p.backoff(attempts, err)
+		f.logger().Warn("retrying GDELT download", "URL", url, "attempt", attempts, "delay", delay, "error", err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return attempts, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if attempts > 1 {
+		err = &RetryError{URL: url, Attempts: attempts, Err: err}
+	}
+	return attempts, err
+}
+
+// parseRetryAfter parses the value of a Retry-After header, expressed
+// either in seconds or as an HTTP date. It returns 0 if the value is
+// missing, invalid or in the past.
+func parseRetryAfter(value string) time.Duration {
+	if len(value) == 0 {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
@@ -0,0 +1,134 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// timeoutError is a net.Error reporting a timeout, like the ones returned
+// when http.Client.Timeout is exceeded.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func urlError(err error) error {
+	return &url.Error{Op: "Get", URL: "http://data.gdeltproject.org/gdeltv2/lastupdate.txt", Err: err}
+}
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"canceled", fmt.Errorf("download: %w", context.Canceled), false},
+		{"deadline exceeded", urlError(context.DeadlineExceeded), false},
+		{"status 404", BadStatusCodeError{StatusCode: 404}, false},
+		{"status 408", BadStatusCodeError{StatusCode: 408}, true},
+		{"status 429", BadStatusCodeError{StatusCode: 429}, true},
+		{"status 503", fmt.Errorf("download: %w", BadStatusCodeError{StatusCode: 503}), true},
+		{"integrity", &IntegrityError{URL: "x", Err: errors.New("MD5 mismatch")}, true},
+		{"client timeout", urlError(timeoutError{}), true},
+		{"connection refused", urlError(&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}), true},
+		{"connection reset", urlError(&net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}), true},
+		{"bare connection reset", fmt.Errorf("read body: %w", syscall.ECONNRESET), true},
+		{"unexpected EOF", urlError(io.ErrUnexpectedEOF), true},
+		{"malformed URL", urlError(errors.New("unsupported protocol scheme \"\"")), false},
+		{"certificate", urlError(x509.UnknownAuthorityError{}), false},
+		{"redirect rejected", urlError(http.ErrUseLastResponse), false},
+		{"other", errors.New("boom"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryableError(tt.err); got != tt.want {
+				t.Errorf("IsRetryableError(%v) = %v, expected %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second, Multiplier: 2}
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		retry  int
+		err    error
+		want   time.Duration
+	}{
+		{"first retry", p, 1, nil, time.Second},
+		{"second retry", p, 2, nil, 2 * time.Second},
+		{"third retry", p, 3, nil, 4 * time.Second},
+		{"capped", p, 4, nil, 5 * time.Second},
+		{"no cap", RetryPolicy{InitialBackoff: time.Second, Multiplier: 2}, 5, nil, 16 * time.Second},
+		{"multiplier below 1", RetryPolicy{InitialBackoff: time.Second, Multiplier: 0.5}, 3, nil, time.Second},
+		{"longer Retry-After", p, 1, BadStatusCodeError{StatusCode: 429, RetryAfter: 30 * time.Second}, 30 * time.Second},
+		{"shorter Retry-After", p, 3, BadStatusCodeError{StatusCode: 429, RetryAfter: time.Second}, 4 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.backoff(tt.retry, tt.err); got != tt.want {
+				t.Errorf("backoff(%d) = %v, expected %v", tt.retry, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoffJitter(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: 10 * time.Second, Multiplier: 2, Jitter: 0.2}
+	for i := 0; i < 100; i++ {
+		if d := p.backoff(1, nil); d < 8*time.Second || d > 12*time.Second {
+			t.Fatalf("backoff(1) = %v, expected a value from 8s to 12s", d)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		min   time.Duration
+		max   time.Duration
+	}{
+		{"missing", "", 0, 0},
+		{"seconds", "120", 2 * time.Minute, 2 * time.Minute},
+		{"zero seconds", "0", 0, 0},
+		{"negative seconds", "-5", 0, 0},
+		{"invalid", "soon", 0, 0},
+		{"future date", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), 58 * time.Minute, time.Hour},
+		{"past date", "Wed, 21 Oct 2015 07:28:00 GMT", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value); got < tt.min || got > tt.max {
+				t.Errorf("parseRetryAfter(%q) = %v, expected a value from %v to %v", tt.value, got, tt.min, tt.max)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Validation selects how the events failing Event.Validate are handled.
+type Validation uint8
+
+const (
+	// SkipValidation does not validate the events.
+	SkipValidation Validation = iota
+	// FlagInvalidEvents reports the invalid events to the ParseErrorHandler,
+	// but keeps them.
+	FlagInvalidEvents
+	// RejectInvalidEvents reports the invalid events to the
+	// ParseErrorHandler, and discards them like malformed records.
+	RejectInvalidEvents
+)
+
+// ValidationError describes an Event field violating the GDELT schema.
+// Event.Validate returns all of them joined with errors.Join: they can be
+// recognized using errors.As.
+type ValidationError struct {
+	Field string
+	Value any
+	Msg   string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s %v: %s", e.Field, e.Value, e.Msg)
+}
+
+// fractionDateRounding is the rounding error of FractionDate, which GDELT
+// writes with four decimal digits.
+const fractionDateRounding = 0.00005
+
+// fractionDateBounds returns the range of the fractional part of
+// FractionDate consistent with day. GDELT documents it as the fraction of the
+// year elapsed, but computes it as ((MONTH-1)*30 + DAY) / 365: the range
+// spans that approximation and the exact fraction, with the day of the year
+// counted from either 0 or 1 over the actual length of the year.
+func fractionDateBounds(day time.Time) (lo, hi float64) {
+	daysInYear := time.Date(day.Year(), time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+	lo = float64(day.YearDay()-1) / float64(daysInYear)
+	hi = float64(day.YearDay()) / float64(daysInYear)
+	approx := float64((int(day.Month())-1)*30+day.Day()) / 365
+	return min(lo, approx) - fractionDateRounding, max(hi, approx) + fractionDateRounding
+}
+
+// Validate checks the consistency of the event fields: dates, QuadClass,
+// GoldsteinScale and AvgTone ranges, geographic types and coordinates, and
+// event code prefixes. It returns all the violations found, joined with
+// errors.Join, or nil.
+//
+// Codes are not checked against the CAMEO taxonomy: see ValidateCameoCodes.
+func (e *Event) Validate() error {
+	var errs []error
+	add := func(field string, value any, format string, a ...any) {
+		errs = append(errs, &ValidationError{Field: field, Value: value, Msg: fmt.Sprintf(format, a...)})
+	}
+
+	day, err := time.Parse("20060102", fmt.Sprintf("%08d", e.Day))
+	if err != nil {
+		add("Day", e.Day, "not a valid YYYYMMDD date")
+	} else {
+		if e.MonthYear != e.Day/100 {
+			add("MonthYear", e.MonthYear, "does not match Day %d", e.Day)
+		}
+		if e.Year != e.Day/10000 {
+			add("Year", e.Year, "does not match Day %d", e.Day)
+		}
+		frac := e.FractionDate - float64(day.Year())
+		if lo, hi := fractionDateBounds(day); frac < lo || frac > hi {
+			add("FractionDate", e.FractionDate, "does not match Day %d", e.Day)
+		}
+	}
+
+	if e.IsRootEvent != 0 && e.IsRootEvent != 1 {
+		add("IsRootEvent", e.IsRootEvent, "expected 0 or 1")
+	}
+	if !strings.HasPrefix(e.EventCode, e.EventBaseCode) {
+		add("EventBaseCode", e.EventBaseCode, "not a prefix of EventCode %q", e.EventCode)
+	}
+	if len(e.EventRootCode) == 0 || !strings.HasPrefix(e.EventBaseCode, e.EventRootCode) {
+		add("EventRootCode", e.EventRootCode, "not a prefix of EventBaseCode %q", e.EventBaseCode)
+	}
+	if e.QuadClass < 1 || e.QuadClass > 4 {
+		add("QuadClass", e.QuadClass, "expected a value from 1 to 4")
+	}
+	if g := e.GoldsteinScale; g.Valid && (g.Float64 < -10 || g.Float64 > 10) {
+		add("GoldsteinScale", g.Float64, "expected a value from -10 to 10")
+	}
+	if e.AvgTone < -100 || e.AvgTone > 100 {
+		add("AvgTone", e.AvgTone, "expected a value from -100 to 100")
+	}
+	for _, c := range []struct {
+		field string
+		value int
+	}{
+		{"NumMentions", e.NumMentions},
+		{"NumSources", e.NumSources},
+		{"NumArticles", e.NumArticles},
+	} {
+		if c.value < 0 {
+			add(c.field, c.value, "expected a non-negative value")
+		}
+	}
+
+	e.Actor1Geo.validate("Actor1Geo", add)
+	e.Actor2Geo.validate("Actor2Geo", add)
+	e.ActionGeo.validate("ActionGeo", add)
+
+	if _, err := e.DateAddedTime(); err != nil {
+		add("DateAdded", e.DateAdded, "not a valid YYYYMMDDHHMMSS time")
+	}
+	return errors.Join(errs...)
+}
+
+func (g *GeoData) validate(prefix string, add func(field string, value any, format string, a ...any)) {
+	if g.Type > WorldState {
+		add(prefix+".Type", int(g.Type), "expected a value from 0 to 5")
+	}
+	if g.Lat.Valid && (g.Lat.Float64 < -90 || g.Lat.Float64 > 90) {
+		add(prefix+".Lat", g.Lat.Float64, "expected a value from -90 to 90")
+	}
+	if g.Long.Valid && (g.Long.Float64 < -180 || g.Long.Float64 > 180) {
+		add(prefix+".Long", g.Long.Float64, "expected a value from -180 to 180")
+	}
+}
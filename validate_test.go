@@ -0,0 +1,164 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// validTestEvent returns an event passing Event.Validate.
+func validTestEvent() *Event {
+	return &Event{
+		Day:            20240614,
+		MonthYear:      202406,
+		Year:           2024,
+		FractionDate:   2024.4493,
+		IsRootEvent:    1,
+		EventCode:      "195",
+		EventBaseCode:  "195",
+		EventRootCode:  "19",
+		QuadClass:      4,
+		GoldsteinScale: NullableFloat64{Float64: -10, Valid: true},
+		AvgTone:        -5.28,
+		NumMentions:    10,
+		NumSources:     1,
+		NumArticles:    10,
+		ActionGeo: GeoData{
+			Type: WorldCity,
+			Lat:  NullableFloat64{Float64: 34.5167, Valid: true},
+			Long: NullableFloat64{Float64: 69.1833, Valid: true},
+		},
+		DateAdded: 20240614003000,
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(e *Event)
+		fields []string
+	}{
+		{"valid", func(e *Event) {}, nil},
+		{"Day", func(e *Event) { e.Day = 20241314 }, []string{"Day"}},
+		{"MonthYear", func(e *Event) { e.MonthYear = 202405 }, []string{"MonthYear"}},
+		{"Year", func(e *Event) { e.Year = 2023 }, []string{"Year"}},
+		{"FractionDate year", func(e *Event) { e.FractionDate = 2023.4493 }, []string{"FractionDate"}},
+		{"FractionDate a week off", func(e *Event) { e.FractionDate = 2024.4685 }, []string{"FractionDate"}},
+		{"IsRootEvent", func(e *Event) { e.IsRootEvent = 2 }, []string{"IsRootEvent"}},
+		{"EventBaseCode", func(e *Event) { e.EventBaseCode = "194" }, []string{"EventBaseCode"}},
+		{"EventRootCode", func(e *Event) { e.EventRootCode = "" }, []string{"EventRootCode"}},
+		{"QuadClass", func(e *Event) { e.QuadClass = 5 }, []string{"QuadClass"}},
+		{"GoldsteinScale", func(e *Event) { e.GoldsteinScale.Float64 = -10.5 }, []string{"GoldsteinScale"}},
+		{"null GoldsteinScale", func(e *Event) { e.GoldsteinScale = NullableFloat64{} }, nil},
+		{"AvgTone", func(e *Event) { e.AvgTone = 101 }, []string{"AvgTone"}},
+		{"NumSources", func(e *Event) { e.NumSources = -1 }, []string{"NumSources"}},
+		{"ActionGeo.Type", func(e *Event) { e.ActionGeo.Type = WorldState + 1 }, []string{"ActionGeo.Type"}},
+		{"ActionGeo.Lat", func(e *Event) { e.ActionGeo.Lat.Float64 = 90.5 }, []string{"ActionGeo.Lat"}},
+		{"Actor1Geo.Long", func(e *Event) { e.Actor1Geo.Long = NullableFloat64{Float64: -181, Valid: true} }, []string{"Actor1Geo.Long"}},
+		{"DateAdded", func(e *Event) { e.DateAdded = 20240614996000 }, []string{"DateAdded"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := validTestEvent()
+			tt.modify(e)
+			if got := validationFields(e.Validate()); strings.Join(got, ",") != strings.Join(tt.fields, ",") {
+				t.Errorf("invalid fields = %v, want %v", got, tt.fields)
+			}
+		})
+	}
+}
+
+func TestValidateFractionDate(t *testing.T) {
+	tests := []struct {
+		day      int
+		fraction float64
+		valid    bool
+	}{
+		// GDELT approximation, ((MONTH-1)*30 + DAY) / 365.
+		{20150218, 2015.1315, true},
+		{20231231, 2023.9890, true},
+		// Fraction of the year elapsed.
+		{19790101, 1979.0000, true},
+		{19790101, 1979.0027, true},
+		{20231231, 2023.9973, true},
+		{20231231, 2024.0000, true},
+		// Leap years.
+		{20240229, 2024.1612, true},
+		{20240301, 2024.1667, true},
+		{20241231, 2024.9973, true},
+		{20241231, 2025.0000, true},
+		{20241231, 2024.9890, true},
+		// Mismatches.
+		{20150218, 2015.1260, false},
+		{20231231, 2024.0030, false},
+		{20241231, 2024.9850, false},
+		{20240701, 2024.4000, false},
+	}
+	for _, tt := range tests {
+		e := validTestEvent()
+		e.Day, e.MonthYear, e.Year = tt.day, tt.day/100, tt.day/10000
+		e.FractionDate = tt.fraction
+		err := e.Validate()
+		if got := err == nil; got != tt.valid {
+			t.Errorf("Day %d, FractionDate %.4f: valid = %v, want %v (%v)", tt.day, tt.fraction, got, tt.valid, err)
+		}
+	}
+}
+
+func TestEventReaderStrict(t *testing.T) {
+	row := strings.Replace(exportTestRows[0], "\t4\t-10.0\t", "\t5\t-10.0\t", 1)
+	r := NewEventReader(strings.NewReader(row + "\n"))
+	r.Strict = true
+
+	ev, err := r.Read()
+	if ev == nil || ev.GlobalEventID != 1179475420 {
+		t.Fatalf("Read returned event %v, want the decoded event", ev)
+	}
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Row != 1 {
+		t.Fatalf("Read error = %v, want a *ParseError for row 1", err)
+	}
+	if got := validationFields(err); !slices.Contains(got, "QuadClass") {
+		t.Errorf("invalid fields = %v, want QuadClass", got)
+	}
+}
+
+// validationFields returns the fields of the *ValidationError values
+// joined in err.
+func validationFields(err error) []string {
+	var fields []string
+	var walk func(err error)
+	walk = func(err error) {
+		if ve, ok := err.(*ValidationError); ok {
+			fields = append(fields, ve.Field)
+			return
+		}
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, e := range u.Unwrap() {
+				walk(e)
+			}
+		case interface{ Unwrap() error }:
+			walk(u.Unwrap())
+		}
+	}
+	if err != nil {
+		walk(err)
+	}
+	return fields
+}
@@ -0,0 +1,217 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultWatchInterval is the default polling interval of a Watcher.
+const DefaultWatchInterval = time.Minute
+
+// Watcher polls the GDELT file lists and delivers each new 15-minute batch
+// exactly once, in chronological order per feed.
+//
+// When a gap between the last delivered batch and the latest one is
+// detected, for example after downtime, the missing batches are enumerated
+// from the master file lists and delivered first.
+//
+// A Watcher must not be used by multiple goroutines at once.
+type Watcher struct {
+	// Fetcher performs the downloads. If nil, DefaultFetcher is used.
+	Fetcher *Fetcher
+	// Opts filters the events of each batch. Opts.Translingual enables
+	// watching the GDELT Translingual feed too.
+	Opts Opts
+	// Interval is the time between two polls. If zero,
+	// DefaultWatchInterval is used.
+	Interval time.Duration
+	// Since, if not zero, makes the watcher catch up on all batches
+	// published at or after it, unless the Checkpoint records a later
+	// batch. Otherwise, only the latest batch is delivered first.
+	Since time.Time
+	// Checkpoint records the last delivered batch of each feed, so that a
+	// new Watcher resumes where the previous one stopped. A batch is
+	// recorded as soon as it is received from the channel. If nil, an
+	// in-memory checkpoint is used.
+	Checkpoint Checkpoint
+}
+
+// Subscribe starts watching in a new goroutine, returning a channel
+// delivering the batches. The channel is closed once ctx is done.
+// Errors are logged and the failed batches are retried at the next poll.
+func (w *Watcher) Subscribe(ctx context.Context) <-chan *Batch {
+	ch := make(chan *Batch)
+	go func() {
+		defer close(ch)
+		_ = w.Watch(ctx, ch)
+	}()
+	return ch
+}
+
+// Watch polls for new batches and sends them to ch until ctx is done,
+// returning the context error. Errors are logged and the failed batches
+// are retried at the next poll, except for batches whose files are missing
+// (HTTP 404 and similar), which are skipped.
+func (w *Watcher) Watch(ctx context.Context, ch chan<- *Batch) error {
+	interval := w.Interval
+	if interval == 0 {
+		interval = DefaultWatchInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, err := w.Poll(ctx, ch)
+		if err != nil && ctx.Err() == nil {
+			w.fetcher().logger().Warn("failed to poll GDELT feeds", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll checks once for new batches, sending them to ch. It returns the
+// number of batches delivered, and the first error encountered. A failure
+// on one feed does not prevent polling the other one.
+func (w *Watcher) Poll(ctx context.Context, ch chan<- *Batch) (delivered int, err error) {
+	for _, fd := range feeds(w.Opts.Translingual) {
+		n, e := w.pollFeed(ctx, fd, ch)
+		delivered += n
+		if e != nil && err == nil {
+			err = fmt.Errorf("failed to poll %s feed: %w", fd, e)
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return delivered, err
+}
+
+func (w *Watcher) pollFeed(ctx context.Context, fd Feed, ch chan<- *Batch) (int, error) {
+	f := w.fetcher()
+	latest, err := f.getFileReferences(ctx, f.url(fd.lastUpdateFile()))
+	if err != nil {
+		return 0, err
+	}
+
+	last, err := w.lastDelivered(fd)
+	if err != nil {
+		return 0, err
+	}
+	if !latest.Time().After(last) {
+		return 0, nil
+	}
+
+	pending := []*fileReferences{latest}
+	if !last.IsZero() && latest.Time().Sub(last) > BatchInterval {
+		missing, err := f.getMasterFileList(ctx, f.url(fd.masterFileListFile()), last.Add(time.Nanosecond), latest.Time())
+		if err != nil {
+			return 0, fmt.Errorf("failed to catch up on missed batches: %w", err)
+		}
+		pending = append(missing, latest)
+	}
+
+	delivered := 0
+	for _, fr := range pending {
+		batch, err := f.getBatch(ctx, fr, w.Opts)
+		if IsBadStatusCodeError(err) {
+			// Missing files would otherwise stall the watcher forever:
+			// skip the batch, recording it as processed.
+			f.logger().Warn("failed to get GDELT batch events", "batch", fr.Time(), "error", err)
+			if err := w.checkpoint().Save(fd, fr.checkpoint()); err != nil {
+				return delivered, fmt.Errorf("failed to save checkpoint: %w", err)
+			}
+			continue
+		}
+		if err != nil {
+			return delivered, fmt.Errorf("failed to get events of batch %s: %w", fr.Time().Format(dateAddedTimeLayout), err)
+		}
+		select {
+		case <-ctx.Done():
+			return delivered, ctx.Err()
+		case ch <- batch:
+		}
+		delivered++
+		if err := w.checkpoint().Save(fd, batch.checkpoint()); err != nil {
+			return delivered, fmt.Errorf("failed to save checkpoint: %w", err)
+		}
+	}
+	return delivered, nil
+}
+
+func (w *Watcher) lastDelivered(fd Feed) (time.Time, error) {
+	bc, ok, err := w.checkpoint().Load(fd)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+	var last time.Time
+	if ok {
+		last = bc.Time
+	}
+	if !w.Since.IsZero() {
+		// The batch published exactly at Since must be delivered too.
+		if since := w.Since.Add(-time.Nanosecond); since.After(last) {
+			last = since
+		}
+	}
+	return last, nil
+}
+
+func (w *Watcher) checkpoint() Checkpoint {
+	if w.Checkpoint == nil {
+		w.Checkpoint = new(MemoryCheckpoint)
+	}
+	return w.Checkpoint
+}
+
+func (w *Watcher) fetcher() *Fetcher {
+	if w.Fetcher == nil {
+		return DefaultFetcher
+	}
+	return w.Fetcher
+}
+
+// FetchNewBatches returns all batches published after the ones recorded in
+// the checkpoint, in chronological order per feed, recording them in the
+// checkpoint. If the checkpoint is empty, only the latest batch of each
+// feed is returned.
+//
+// It is meant for periodic jobs: each call resumes exactly where the
+// previous one stopped. On error, the batches fetched so far are returned
+// along with it, and are recorded in the checkpoint.
+func (f *Fetcher) FetchNewBatches(ctx context.Context, cp Checkpoint, opts Opts) ([]*Batch, error) {
+	w := &Watcher{Fetcher: f, Opts: opts, Checkpoint: cp}
+
+	ch := make(chan *Batch)
+	done := make(chan struct{})
+	var batches []*Batch
+	go func() {
+		defer close(done)
+		for batch := range ch {
+			batches = append(batches, batch)
+		}
+	}()
+
+	_, err := w.Poll(ctx, ch)
+	close(ch)
+	<-done
+	return batches, err
+}
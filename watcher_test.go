@@ -0,0 +1,54 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWatcherLastDelivered(t *testing.T) {
+	since := time.Date(2024, 6, 14, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		since      time.Time
+		checkpoint time.Time
+		want       time.Time
+	}{
+		{"nothing", time.Time{}, time.Time{}, time.Time{}},
+		{"since only", since, time.Time{}, since.Add(-time.Nanosecond)},
+		{"checkpoint only", time.Time{}, since, since},
+		{"checkpoint after since", since, since.Add(time.Hour), since.Add(time.Hour)},
+		{"checkpoint before since", since, since.Add(-time.Hour), since.Add(-time.Nanosecond)},
+		{"checkpoint at since", since, since, since},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Watcher{Since: tt.since}
+			if !tt.checkpoint.IsZero() {
+				if err := w.checkpoint().Save(EnglishFeed, BatchCheckpoint{Time: tt.checkpoint}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := w.lastDelivered(EnglishFeed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("expected %v, actual %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// EventWriter writes events in the GDELT 2.0 export tab-separated format,
+// so that the output can be read back by EventReader and by any other tool
+// consuming GDELT export files.
+//
+// Fields are never quoted, and numbers are formatted as GDELT does, so that
+// records read from a GDELT 2.0 export file are written back byte-identical.
+type EventWriter struct {
+	w *bufio.Writer
+}
+
+// NewEventWriter returns a new EventWriter writing to w.
+func NewEventWriter(w io.Writer) *EventWriter {
+	return &EventWriter{w: bufio.NewWriter(w)}
+}
+
+// Write writes a single event. Writes are buffered, so Flush must
+// eventually be called to ensure that the event is written.
+//
+// Events with a field containing a tab or a newline cannot be represented
+// in the export format, and are rejected.
+func (w *EventWriter) Write(e *Event) error {
+	csvRecord := formatEventRecord(e)
+	for i, field := range csvRecord {
+		if strings.ContainsAny(field, "\t\r\n") {
+			return fmt.Errorf("event %d: column %d contains a tab or newline", e.GlobalEventID, i)
+		}
+	}
+	_, err := w.w.WriteString(strings.Join(csvRecord, "\t") + "\n")
+	return err
+}
+
+// WriteAll writes multiple events and then calls Flush.
+func (w *EventWriter) WriteAll(events []*Event) error {
+	for _, e := range events {
+		if err := w.Write(e); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// Flush writes any buffered data to the underlying io.Writer, returning
+// any error that occurred during a previous Write or the Flush itself.
+func (w *EventWriter) Flush() error {
+	return w.w.Flush()
+}
+
+func formatEventRecord(e *Event) []string {
+	csvRecord := make([]string, 0, 61)
+
+	csvRecord = append(csvRecord,
+		strconv.FormatUint(e.GlobalEventID, 10),
+		strconv.Itoa(e.Day),
+		strconv.Itoa(e.MonthYear),
+		strconv.Itoa(e.Year),
+		strconv.FormatFloat(e.FractionDate, 'f', 4, 64),
+	)
+
+	csvRecord = appendActorData(csvRecord, e.Actor1)
+	csvRecord = appendActorData(csvRecord, e.Actor2)
+
+	csvRecord = append(csvRecord,
+		strconv.Itoa(e.IsRootEvent),
+		e.EventCode,
+		e.EventBaseCode,
+		e.EventRootCode,
+		strconv.Itoa(e.QuadClass),
+		formatGoldsteinScale(e.GoldsteinScale),
+		strconv.Itoa(e.NumMentions),
+		strconv.Itoa(e.NumSources),
+		strconv.Itoa(e.NumArticles),
+		formatFloat(e.AvgTone),
+	)
+
+	csvRecord = appendGeoData(csvRecord, e.Actor1Geo)
+	csvRecord = appendGeoData(csvRecord, e.Actor2Geo)
+	csvRecord = appendGeoData(csvRecord, e.ActionGeo)
+
+	csvRecord = append(csvRecord,
+		fmt.Sprintf("%014d", e.DateAdded),
+		e.SourceURL,
+	)
+	return csvRecord
+}
+
+func appendActorData(csvRecord []string, a ActorData) []string {
+	return append(csvRecord,
+		a.Code,
+		a.Name,
+		a.CountryCode,
+		a.KnownGroupCode,
+		a.EthnicCode,
+		a.Religion1Code,
+		a.Religion2Code,
+		a.Type1Code,
+		a.Type2Code,
+		a.Type3Code,
+	)
+}
+
+func appendGeoData(csvRecord []string, g GeoData) []string {
+	return append(csvRecord,
+		strconv.Itoa(int(g.Type)),
+		g.Fullname,
+		g.CountryCode,
+		g.ADM1Code,
+		g.ADM2Code,
+		formatNullableFloat64(g.Lat),
+		formatNullableFloat64(g.Long),
+		g.FeatureID,
+	)
+}
+
+// formatFloat formats a float with the minimum number of digits necessary
+// to represent it exactly, as GDELT does for AvgTone and coordinates.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// formatGoldsteinScale formats a GoldsteinScale value, which GDELT always
+// writes with one decimal digit, such as "-10.0".
+func formatGoldsteinScale(n NullableFloat64) string {
+	if !n.Valid {
+		return ""
+	}
+	return strconv.FormatFloat(n.Float64, 'f', 1, 64)
+}
+
+// formatNullableFloat64 formats a NullableFloat64, writing invalid values
+// as empty strings.
+func formatNullableFloat64(n NullableFloat64) string {
+	if !n.Valid {
+		return ""
+	}
+	return formatFloat(n.Float64)
+}
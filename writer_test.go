@@ -0,0 +1,158 @@
+// Copyright 2023 The NLP Odyssey Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gdelt
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// exportRow joins the columns of a GDELT 2.0 export record.
+func exportRow(columns ...[]string) string {
+	var fields []string
+	for _, c := range columns {
+		fields = append(fields, c...)
+	}
+	return strings.Join(fields, "\t")
+}
+
+var exportTestRows = []string{
+	exportRow(
+		[]string{"1179475420", "20240614", "202406", "2024", "2024.4466"},
+		[]string{"USA", "UNITED STATES", "USA", "", "", "", "", "", "", ""},
+		[]string{"AFGGOV", "AFGHANISTAN", "AFG", "", "", "", "", "GOV", "", ""},
+		[]string{"1", "195", "195", "19", "4", "-10.0", "10", "1", "10", "-5.28455284552845"},
+		[]string{"1", "United States", "US", "US", "", "39.828175", "-98.5795", "US"},
+		[]string{"1", "Afghanistan", "AF", "AF", "", "33", "65", "AF"},
+		[]string{"4", "Kabul, Kabol, Afghanistan", "AF", "AF13", "", "34.5167", "69.1833", "-3378435"},
+		[]string{"20240614003000", "https://www.example.com/news/2024/06/14/story.html"},
+	),
+	exportRow(
+		[]string{"1179475421", "20240101", "202401", "2024", "2024.0027"},
+		[]string{"", "", "", "", "", "", "", "", "", ""},
+		[]string{"", "", "", "", "", "", "", "", "", ""},
+		[]string{"0", "010", "010", "01", "1", "", "2", "1", "2", "0"},
+		[]string{"0", "", "", "", "", "", "", ""},
+		[]string{"0", "", "", "", "", "", "", ""},
+		[]string{"3", "Springfield, Illinois, United States", "US", "USIL", "IL167", "39.8017", "-89.6437", "2396279"},
+		[]string{"20240101000000", "https://example.org/a?b=c&d=e"},
+	),
+	exportRow(
+		[]string{"1179475422", "20231231", "202312", "2023", "2023.9973"},
+		[]string{"REB", "REBEL", "", "", "", "", "", "REB", "", ""},
+		[]string{"FRA", "FRANCE", "FRA", "", "", "", "", "", "", ""},
+		[]string{"1", "0231", "023", "02", "1", "1.0", "4", "2", "4", "1.09289617486339"},
+		[]string{"4", "Paris, Ile-de-France, France", "FR", "FRA8", "", "", "", "-1456928"},
+		[]string{"1", "France", "FR", "FR", "", "46", "2", "FR"},
+		[]string{"5", "Bavaria, Germany", "GM", "GM02", "", "49", "11.5", "GM02"},
+		[]string{"20240102031500", "http://example.net/"},
+	),
+	exportRow(
+		[]string{"1179475423", "20240614", "202406", "2024", "2024.4493"},
+		[]string{"", " LEADING", "", "", "", "", "", "", "", ""},
+		[]string{"", "", "", "", "", "", "", "", "", ""},
+		[]string{"1", "043", "043", "04", "1", "2.8", "2", "1", "2", "0.5"},
+		[]string{"0", "", "", "", "", "", "", ""},
+		[]string{"0", "", "", "", "", "", "", ""},
+		[]string{"0", "", "", "", "", "", "", ""},
+		[]string{"20240614003000", `https://x.com/a"b`},
+	),
+}
+
+func TestEventWriterRoundTrip(t *testing.T) {
+	input := strings.Join(exportTestRows, "\n") + "\n"
+
+	r := NewEventReader(strings.NewReader(input))
+	var buf bytes.Buffer
+	w := NewEventWriter(&buf)
+	for {
+		ev, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Read: %v", err)
+		}
+		if err := w.Write(ev); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	if buf.String() == input {
+		return
+	}
+	got := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(got) != len(exportTestRows) {
+		t.Fatalf("expected %d rows, actual %d", len(exportTestRows), len(got))
+	}
+	for i, want := range exportTestRows {
+		wantFields := strings.Split(want, "\t")
+		gotFields := strings.Split(got[i], "\t")
+		if len(gotFields) != len(wantFields) {
+			t.Errorf("row %d: expected %d columns, actual %d", i, len(wantFields), len(gotFields))
+			continue
+		}
+		for j := range wantFields {
+			if gotFields[j] != wantFields[j] {
+				t.Errorf("row %d column %d (%s): expected %q, actual %q", i, j, eventColumns[j], wantFields[j], gotFields[j])
+			}
+		}
+	}
+	t.Errorf("expected output %q, actual %q", input, buf.String())
+}
+
+func TestEventWriterRejectsSeparators(t *testing.T) {
+	for _, url := range []string{"https://x.com/a\tb", "https://x.com/a\nb"} {
+		w := NewEventWriter(io.Discard)
+		if err := w.Write(&Event{GlobalEventID: 1, SourceURL: url}); err == nil {
+			t.Errorf("Write with SourceURL %q: expected an error", url)
+		}
+	}
+}
+
+func TestFormatEventRecord(t *testing.T) {
+	tests := []struct {
+		name   string
+		event  Event
+		column int
+		want   string
+	}{
+		{"FractionDate padding", Event{FractionDate: 2024.01}, 4, "2024.0100"},
+		{"GoldsteinScale integer", Event{GoldsteinScale: NullableFloat64{Float64: -10, Valid: true}}, 30, "-10.0"},
+		{"GoldsteinScale zero", Event{GoldsteinScale: NullableFloat64{Float64: 0, Valid: true}}, 30, "0.0"},
+		{"GoldsteinScale null", Event{}, 30, ""},
+		{"AvgTone", Event{AvgTone: -3.09278350515464}, 34, "-3.09278350515464"},
+		{"Lat null", Event{}, 56, ""},
+		{"Long integer", Event{ActionGeo: GeoData{Long: NullableFloat64{Float64: 65, Valid: true}}}, 57, "65"},
+		{"DateAdded", Event{DateAdded: 20240102031500}, 59, "20240102031500"},
+		{"DateAdded zero padding", Event{}, 59, "00000000000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := formatEventRecord(&tt.event)
+			if len(record) != 61 {
+				t.Fatalf("expected 61 columns, actual %d", len(record))
+			}
+			if got := record[tt.column]; got != tt.want {
+				t.Errorf("column %d (%s): expected %q, actual %q", tt.column, eventColumns[tt.column], tt.want, got)
+			}
+		})
+	}
+}